@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/astronoka/migu"
+)
+
+type diff struct {
+	Dialect   string   `long:"dialect" default:"mysql"`
+	OldFormat string   `long:"old-format"`
+	Include   []string `long:"include"`
+	Exclude   []string `long:"exclude"`
+	Help      bool     `long:"help"`
+}
+
+func (d *diff) Usage() string {
+	return fmt.Sprintf(`Usage: %s diff [OPTIONS] OLD_FILE NEW_FILE
+
+Options:
+      --dialect=NAME     Dialect of database (mysql, postgres, sqlite3) [default: mysql]
+      --old-format=NAME  Format of OLD_FILE (go, sql) [default: by the extension]
+      --include=PATTERN  Manage only the tables that match PATTERN
+      --exclude=PATTERN  Don't manage the tables that match PATTERN
+                         PATTERN is a glob pattern, or a regular expression
+                         enclosed in slashes (e.g. /^_.*_(gho|del)$/).
+                         These can be specified multiple times
+      --help             Display this help and exit
+
+Print the SQL to migrate the schema in OLD_FILE to NEW_FILE.
+If OLD_FILE has the .sql extension or --old-format=sql is specified, it's
+read as the DDL snapshot of the database (e.g. the output of
+mysqldump --no-data).
+When either OLD_FILE or NEW_FILE is -, read standard input. Both of them
+can't be -. Specify --old-format=sql to read the snapshot from standard
+input.
+`, progName)
+}
+
+func (d *diff) ShowHelp() bool {
+	return d.Help
+}
+
+func (d *diff) Execute(args []string) error {
+	switch {
+	case len(args) < 2:
+		return &usageError{
+			err: fmt.Errorf("too few arguments"),
+		}
+	case len(args) > 2:
+		return &usageError{
+			err: fmt.Errorf("too many arguments"),
+		}
+	case args[0] == "-" && args[1] == "-":
+		return &usageError{
+			err: fmt.Errorf("standard input can't be both OLD_FILE and NEW_FILE"),
+		}
+	}
+	isSQL, err := d.isSQL(args[0])
+	if err != nil {
+		return err
+	}
+	sqlDialect, err := sqlDialect(d.Dialect)
+	if err != nil {
+		return err
+	}
+	opts := []migu.Option{migu.WithDialect(sqlDialect), migu.WithInclude(d.Include...), migu.WithExclude(d.Exclude...)}
+	oldFile, oldSrc := source(args[0])
+	newFile, newSrc := source(args[1])
+	var sqls []string
+	if isSQL {
+		if oldFile == "" {
+			// the snapshot is read from the file.
+			if oldFile, err = tempFile(oldSrc); err != nil {
+				return err
+			}
+			defer os.Remove(oldFile)
+		}
+		sqls, err = migu.Diff(nil, newFile, newSrc, append(opts, migu.WithSnapshot(oldFile))...)
+	} else {
+		sqls, err = migu.DiffFiles(oldFile, oldSrc, newFile, newSrc, opts...)
+	}
+	if err != nil {
+		return err
+	}
+	for _, sql := range sqls {
+		fmt.Printf("%s;\n", sql)
+	}
+	return nil
+}
+
+// isSQL reports whether the old file is the DDL snapshot by --old-format or
+// the extension of the file.
+func (d *diff) isSQL(file string) (bool, error) {
+	switch d.OldFormat {
+	case "":
+		return strings.HasSuffix(file, ".sql"), nil
+	case "go":
+		return false, nil
+	case "sql":
+		return true, nil
+	default:
+		return false, &usageError{
+			err: fmt.Errorf("unknown format of OLD_FILE: %s", d.OldFormat),
+		}
+	}
+}
+
+// tempFile writes the content of r to the temporary file, and returns its
+// name.
+func tempFile(r io.Reader) (string, error) {
+	f, err := ioutil.TempFile("", "migu-*.sql")
+	if err != nil {
+		return "", err
+	}
+	_, err = io.Copy(f, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// source returns the arguments for the filename and the src parameters of
+// migu's functions.
+func source(file string) (string, io.Reader) {
+	if file == "-" {
+		return "", os.Stdin
+	}
+	return file, nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiffExecuteArgs(t *testing.T) {
+	for i, v := range []struct {
+		args   []string
+		expect string
+	}{
+		{[]string{"old.go"}, "too few arguments"},
+		{[]string{"old.go", "new.go", "more.go"}, "too many arguments"},
+		{[]string{"-", "-"}, "standard input can't be both OLD_FILE and NEW_FILE"},
+	} {
+		err := (&diff{Dialect: "mysql"}).Execute(v.args)
+		uerr, ok := err.(*usageError)
+		if !ok || uerr.err.Error() != v.expect {
+			t.Errorf("%d: (&diff{}).Execute(%#v) => %#v; want %q", i, v.args, err, v.expect)
+		}
+	}
+	err := (&diff{Dialect: "mysql", OldFormat: "yaml"}).Execute([]string{"old.yaml", "new.go"})
+	if uerr, ok := err.(*usageError); !ok || uerr.err.Error() != "unknown format of OLD_FILE: yaml" {
+		t.Errorf("(&diff{OldFormat: \"yaml\"}).Execute(...) => %#v; want the usage error", err)
+	}
+}
+
+func TestDiffExecute(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"old.sql": "CREATE TABLE `user` (\n" +
+			"  `id` bigint NOT NULL,\n" +
+			"  PRIMARY KEY (`id`)\n" +
+			");\n" +
+			"CREATE TABLE `_user_gho` (\n" +
+			"  `id` bigint NOT NULL\n" +
+			");\n",
+		"old.go": "package schema\n" +
+			"type User struct {\n" +
+			"	ID int64 `migu:\"pk\"`\n" +
+			"}\n" +
+			"type Post struct {\n" +
+			"	ID int64 `migu:\"pk\"`\n" +
+			"}\n",
+		"new.go": "package schema\n" +
+			"type User struct {\n" +
+			"	ID   int64 `migu:\"pk\"`\n" +
+			"	Name string\n" +
+			"}\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, v := range []struct {
+		cmd    *diff
+		stdin  string
+		args   []string
+		expect string
+	}{
+		{
+			// the table of the other tool isn't dropped.
+			cmd:    &diff{Dialect: "mysql", Exclude: []string{"/^_.*_gho$/"}},
+			args:   []string{"old.sql", "new.go"},
+			expect: "ALTER TABLE `user` ADD `name` VARCHAR(255) NOT NULL AFTER `id`;\n",
+		},
+		{
+			cmd:    &diff{Dialect: "mysql", OldFormat: "sql", Include: []string{"user"}},
+			stdin:  "old.sql",
+			args:   []string{"-", "new.go"},
+			expect: "ALTER TABLE `user` ADD `name` VARCHAR(255) NOT NULL AFTER `id`;\n",
+		},
+		{
+			cmd:    &diff{Dialect: "mysql", Include: []string{"user"}},
+			args:   []string{"old.go", "new.go"},
+			expect: "ALTER TABLE `user` ADD `name` VARCHAR(255) NOT NULL AFTER `id`;\n",
+		},
+	} {
+		args := make([]string, len(v.args))
+		for j, arg := range v.args {
+			args[j] = arg
+			if arg != "-" {
+				args[j] = filepath.Join(dir, arg)
+			}
+		}
+		stdin := os.DevNull
+		if v.stdin != "" {
+			stdin = filepath.Join(dir, v.stdin)
+		}
+		actual, err := executeDiff(t, v.cmd, stdin, args)
+		if err != nil {
+			t.Errorf("%d: (%#v).Execute(%#v) => %v", i, v.cmd, v.args, err)
+			continue
+		}
+		if actual != v.expect {
+			t.Errorf("%d: (%#v).Execute(%#v) => %q; want %q", i, v.cmd, v.args, actual, v.expect)
+		}
+	}
+}
+
+// executeDiff executes the command with the standard input from stdin and
+// returns the standard output.
+func executeDiff(t *testing.T, cmd *diff, stdin string, args []string) (string, error) {
+	in, err := os.Open(stdin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	out, err := ioutil.TempFile("", "migu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(out.Name())
+	defer out.Close()
+	origStdin, origStdout := os.Stdin, os.Stdout
+	os.Stdin, os.Stdout = in, out
+	err = cmd.Execute(args)
+	os.Stdin, os.Stdout = origStdin, origStdout
+	if err != nil {
+		return "", err
+	}
+	b, err := ioutil.ReadFile(out.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b), nil
+}
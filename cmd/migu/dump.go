@@ -3,21 +3,33 @@ package main
 import (
 	"database/sql"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/astronoka/migu"
+	"github.com/astronoka/migu/dialect"
 )
 
 type dump struct {
 	GeneralOption
+
+	Format    string `long:"format" default:"go"`
+	NullTypes bool   `long:"null-types"`
 }
 
 func (d *dump) Usage() string {
 	return fmt.Sprintf(`Usage: %s dump [OPTIONS] DATABASE [FILE]
 
 Options:
+      --format=FORMAT    Output format (go, sql) [default: go]
+      --null-types       Use the Null types of database/sql such as
+                         sql.NullInt64 for the nullable columns
 %s
 With FILE, output to FILE.
+With --format=sql, output the CREATE TABLE statements instead of Go code.
+If DATABASE has the .go extension, dump the schema in the Go file instead of
+the database. It requires --format=sql.
 `, progName, d.GeneralOption.Usage())
 }
 
@@ -38,23 +50,54 @@ func (d *dump) Execute(args []string) error {
 			err: fmt.Errorf("too many arguments"),
 		}
 	}
-	db, err := database(d.Host, d.User, d.Password, dbname)
+	switch d.Format {
+	case "go", "sql":
+	default:
+		return fmt.Errorf("unknown format: %s", d.Format)
+	}
+	sqlDialect, err := d.SQLDialect()
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(dbname, ".go") {
+		if d.Format != "sql" {
+			return fmt.Errorf("dumping the Go file requires --format=sql")
+		}
+		return d.output(filename, func(out io.Writer) error {
+			return migu.FprintFileSQL(out, dbname, nil, append(d.TableOptions(), migu.WithDialect(sqlDialect))...)
+		})
+	}
+	db, err := database(d.Dialect, d.Host, d.User, d.Password, dbname)
 	if err != nil {
 		return err
 	}
 	defer db.Close()
-	return d.run(db, filename)
+	return d.run(db, sqlDialect, filename)
 }
 
-func (d *dump) run(db *sql.DB, filename string) error {
-	out := os.Stdout
-	if filename != "" {
-		file, err := os.Create(filename)
-		if err != nil {
-			return err
+func (d *dump) run(db *sql.DB, sqlDialect dialect.Dialect, filename string) error {
+	opts := append(d.TableOptions(), migu.WithDialect(sqlDialect))
+	if d.NullTypes {
+		opts = append(opts, migu.WithNullTypes())
+	}
+	return d.output(filename, func(out io.Writer) error {
+		if d.Format == "sql" {
+			return migu.FprintSQL(out, db, opts...)
 		}
-		defer file.Close()
-		out = file
+		return migu.Fprint(out, db, opts...)
+	})
+}
+
+// output calls fn with the file of filename, or with the standard output if
+// filename is empty.
+func (d *dump) output(filename string, fn func(out io.Writer) error) error {
+	if filename == "" {
+		return fn(os.Stdout)
+	}
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
 	}
-	return migu.Fprint(out, db)
+	defer file.Close()
+	return fn(file)
 }
@@ -3,12 +3,17 @@ package main
 import (
 	"database/sql"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 
+	"github.com/astronoka/migu"
+	"github.com/astronoka/migu/dialect"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/howeyc/gopass"
 	"github.com/jessevdk/go-flags"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 )
 
 var (
@@ -23,7 +28,8 @@ var (
 
 Commands:
   sync      synchronize the database schema
-  dump      dump the database schema as Go code
+  dump      dump the database schema as Go code or SQL
+  diff      print the migration SQL between two Go schema files
 
 Options:
       --help             Display this help and exit
@@ -36,21 +42,52 @@ func versionText() string {
 }
 
 type GeneralOption struct {
-	User     string `short:"u" long:"user"`
-	Host     string `short:"h" long:"host"`
-	Password string `short:"p" long:"password" optional:"true" optional-value:"\x00"`
-	Help     bool   `long:"help"`
+	Dialect  string   `long:"dialect" default:"mysql"`
+	User     string   `short:"u" long:"user"`
+	Host     string   `short:"h" long:"host"`
+	Password string   `short:"p" long:"password" optional:"true" optional-value:"\x00"`
+	Include  []string `long:"include"`
+	Exclude  []string `long:"exclude"`
+	Help     bool     `long:"help"`
 }
 
 func (o *GeneralOption) Usage() string {
 	return "" +
+		"      --dialect=NAME     Dialect of database (mysql, postgres, sqlite3) [default: mysql]\n" +
 		"  -u, --user=NAME        User for login to database if not current user\n" +
 		"  -h, --host=HOST        Connect to host of database\n" +
 		"  -p, --password[=PASS]  Password to use when connecting to server.\n" +
 		"                         If password is not given, it's asked from the tty\n" +
+		"      --include=PATTERN  Manage only the tables that match PATTERN\n" +
+		"      --exclude=PATTERN  Don't manage the tables that match PATTERN\n" +
+		"                         PATTERN is a glob pattern, or a regular expression\n" +
+		"                         enclosed in slashes (e.g. /^_.*_(gho|del)$/).\n" +
+		"                         These can be specified multiple times\n" +
 		"      --help             Display this help and exit\n"
 }
 
+func (o *GeneralOption) SQLDialect() (dialect.Dialect, error) {
+	return sqlDialect(o.Dialect)
+}
+
+func sqlDialect(name string) (dialect.Dialect, error) {
+	switch name {
+	case "mysql":
+		return &dialect.MySQL{}, nil
+	case "postgres":
+		return &dialect.PostgreSQL{}, nil
+	case "sqlite3":
+		return &dialect.SQLite3{}, nil
+	default:
+		return nil, fmt.Errorf("unknown dialect: %s", name)
+	}
+}
+
+// TableOptions returns the options to limit the tables managed by migu.
+func (o *GeneralOption) TableOptions() []migu.Option {
+	return []migu.Option{migu.WithInclude(o.Include...), migu.WithExclude(o.Exclude...)}
+}
+
 func (o *GeneralOption) ShowHelp() bool {
 	return o.Help
 }
@@ -83,6 +120,8 @@ func run(args []string) error {
 		cmd = &sync{}
 	case "dump":
 		cmd = &dump{}
+	case "diff":
+		cmd = &diff{}
 	default:
 		return &usageError{
 			usage: usage,
@@ -110,7 +149,11 @@ func run(args []string) error {
 	return nil
 }
 
-func database(host, user, password, dbname string) (db *sql.DB, err error) {
+func database(dialectName, host, user, password, dbname string) (db *sql.DB, err error) {
+	if dialectName == "sqlite3" {
+		// DATABASE is the path of the database file.
+		return sql.Open("sqlite3", dbname)
+	}
 	if user == "" {
 		if user = os.Getenv("USERNAME"); user == "" {
 			if user = os.Getenv("USER"); user == "" {
@@ -118,16 +161,19 @@ func database(host, user, password, dbname string) (db *sql.DB, err error) {
 			}
 		}
 	}
+	if password == "\x00" {
+		fmt.Printf("Enter password: ")
+		p, err := gopass.GetPasswd()
+		if err != nil {
+			return nil, err
+		}
+		password = string(p)
+	}
+	if dialectName == "postgres" {
+		return sql.Open("postgres", postgresDSN(host, user, password, dbname))
+	}
 	dsn := []byte(user)
 	if password != "" {
-		if password == "\x00" {
-			fmt.Printf("Enter password: ")
-			p, err := gopass.GetPasswd()
-			if err != nil {
-				return nil, err
-			}
-			password = string(p)
-		}
 		dsn = append(append(dsn, ':'), password...)
 	}
 	if len(dsn) > 0 {
@@ -140,6 +186,20 @@ func database(host, user, password, dbname string) (db *sql.DB, err error) {
 	return sql.Open("mysql", string(dsn))
 }
 
+func postgresDSN(host, user, password, dbname string) string {
+	u := &url.URL{
+		Scheme:   "postgres",
+		User:     url.User(user),
+		Host:     host,
+		Path:     "/" + dbname,
+		RawQuery: "sslmode=disable",
+	}
+	if password != "" {
+		u.User = url.UserPassword(user, password)
+	}
+	return u.String()
+}
+
 func newParser(option interface{}) (*flags.Parser, error) {
 	parser := flags.NewNamedParser(progName, flags.PrintErrors|flags.PassDoubleDash|flags.PassAfterNonOption)
 	if _, err := parser.AddGroup("", "", option); err != nil {
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/astronoka/migu"
+	"github.com/astronoka/migu/dialect"
 )
 
 var (
@@ -18,8 +19,10 @@ var (
 type sync struct {
 	GeneralOption
 
-	DryRun bool `long:"dry-run"`
-	Quiet  bool `short:"q" long:"quiet"`
+	DryRun                 bool     `long:"dry-run"`
+	Quiet                  bool     `short:"q" long:"quiet"`
+	AllowDestructive       bool     `long:"allow-destructive"`
+	AllowDestructiveTables []string `long:"allow-destructive-table"`
 }
 
 func (s *sync) Usage() string {
@@ -28,8 +31,15 @@ func (s *sync) Usage() string {
 Options:
       --dry-run          Print the results with no changes
   -q, --quiet            Suppress non-error messages
+      --allow-destructive
+                         Allow the lossy or destructive changes
+      --allow-destructive-table=TABLE
+                         Allow the lossy or destructive changes on TABLE
+                         (can be specified multiple times)
 %s
 With no FILE, or when FILE is -, read standard input.
+The changes that may lose the data (e.g. dropping the table or the column,
+narrowing the type of the column) are refused unless they're allowed.
 `, progName, s.GeneralOption.Usage())
 }
 
@@ -50,7 +60,11 @@ func (s *sync) Execute(args []string) error {
 			err: fmt.Errorf("too many arguments"),
 		}
 	}
-	db, err := database(s.Host, s.User, s.Password, dbname)
+	sqlDialect, err := s.SQLDialect()
+	if err != nil {
+		return err
+	}
+	db, err := database(s.Dialect, s.Host, s.User, s.Password, dbname)
 	if err != nil {
 		return err
 	}
@@ -60,44 +74,47 @@ func (s *sync) Execute(args []string) error {
 	}
 	s.printf("======== %ssync ========\n", dryRunMarker)
 	defer s.printf("======== %sdone ========\n", dryRunMarker)
-	return s.run(db, file)
+	return s.run(db, sqlDialect, file)
 }
 
-func (s *sync) run(db *sql.DB, file string) error {
+func (s *sync) run(db *sql.DB, sqlDialect dialect.Dialect, file string) error {
 	var src io.Reader
 	switch file {
 	case "", "-":
 		file = ""
 		src = os.Stdin
 	}
-	sqls, err := migu.Diff(db, file, src)
-	if err != nil {
-		return err
+	opts := append(s.TableOptions(), migu.WithDialect(sqlDialect))
+	if !s.AllowDestructive {
+		opts = append(opts, migu.WithGuard(s.AllowDestructiveTables...))
 	}
-	var tx *sql.Tx
-	if !s.DryRun {
-		if tx, err = db.Begin(); err != nil {
-			return err
+	sqls, err := migu.Diff(db, file, src, opts...)
+	if err != nil {
+		if _, ok := err.(*migu.UnsafeError); ok {
+			return fmt.Errorf("%v\nuse --allow-destructive or --allow-destructive-table=TABLE to apply them", err)
 		}
+		return err
 	}
-	for _, sql := range sqls {
+	exec := func(tx *sql.Tx, sql string) error {
 		s.printf("--------%sapplying--------\n", dryRunMarker)
 		s.printf("  %s\n", strings.Replace(sql, "\n", "\n  ", -1))
 		start := time.Now()
 		if !s.DryRun {
 			if _, err := tx.Exec(sql); err != nil {
-				tx.Rollback()
 				return err
 			}
 		}
 		d := time.Since(start)
 		s.printf("--------%sdone %.3fs--------\n", dryRunMarker, d.Seconds()/time.Second.Seconds())
+		return nil
 	}
 	if s.DryRun {
+		for _, sql := range sqls {
+			exec(nil, sql)
+		}
 		return nil
-	} else {
-		return tx.Commit()
 	}
+	return migu.Exec(db, sqls, exec, opts...)
 }
 
 func (s *sync) printf(format string, a ...interface{}) (int, error) {
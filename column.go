@@ -1,59 +1,315 @@
 package migu
 
 import (
-	"database/sql"
 	"fmt"
 	"go/ast"
 	"go/token"
-	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
+
+	"github.com/astronoka/migu/schema"
 )
 
-type columnSchema struct {
-	TableName              string
-	ColumnName             string
-	OrdinalPosition        int64
-	ColumnDefault          sql.NullString
-	IsNullable             string
-	DataType               string
-	CharacterMaximumLength *uint64
-	CharacterOctetLength   sql.NullInt64
-	NumericPrecision       sql.NullInt64
-	NumericScale           sql.NullInt64
-	ColumnType             string
-	ColumnKey              string
-	Extra                  string
-	ColumnComment          string
-	NonUnique              int64
-	IndexName              string
+// defaultDecimalPrecision is the precision of DECIMAL if not specified.
+const defaultDecimalPrecision = 10
+
+// column returns the column of the schema from the field.
+// The field that has the `type' tag is the type regardless of its Go's type.
+// Similarly, the field that has the tag of the time type such as `date' is
+// the type, and the field of the type that isn't supported is DECIMAL if it
+// has the `precision' or `scale' tag, so the decimal types of any library can
+// be used.
+// The field that has the values of ENUM or SET is the type as well.
+// It returns the error if the Go's type isn't supported and the field
+// doesn't have any of these tags.
+func (f *field) column() (*schema.Column, error) {
+	typ, unsigned, null, err := columnType(f.Type)
+	size, precision, scale, values := f.Size, f.Precision, f.Scale, f.Values
+	switch {
+	case f.SQLType != "":
+		t := parseSQLType(f.SQLType)
+		typ, unsigned, null = t.Type, t.Unsigned, isNullableType(f.Type)
+		size, precision, scale, values = t.Size, t.Precision, t.Scale, t.Values
+	case f.EnumType != "":
+		typ, unsigned, null = f.EnumType, false, isNullableType(f.Type)
+	case f.TimeType != "":
+		typ, unsigned, null = f.TimeType, false, isNullableType(f.Type)
+	case (f.Precision > 0 || f.Scale > 0) && (err != nil || typ == "DECIMAL"):
+		typ, unsigned, null = "DECIMAL", false, isNullableType(f.Type)
+	case err != nil:
+		return nil, err
+	}
+	column := &schema.Column{
+		Name:          f.Name,
+		RenamedFrom:   f.RenamedFrom,
+		Type:          typ,
+		Size:          size,
+		Unsigned:      unsigned,
+		Nullable:      null,
+		Charset:       f.Charset,
+		Collation:     f.Collation,
+		Default:       f.Default,
+		DefaultExpr:   f.DefaultExpr,
+		OnUpdate:      f.UpdateExpr,
+		Generated:     f.Generated,
+		Stored:        f.Stored,
+		AutoIncrement: f.AutoIncrement,
+		Unique:        f.Unique,
+		Comment:       f.Comment,
+	}
+	if column.Type == "BOOL" && column.Default != "" && !column.DefaultExpr {
+		column.Default = normalizeBoolDefaultTagTo0or1(column.Default)
+	}
+	if !column.IsString() && !column.IsBinary() {
+		column.Size = 0
+	}
+	switch {
+	case column.Type == "DECIMAL":
+		column.Precision = decimalPrecision(precision)
+		column.Scale = scale
+	case column.HasFractionalSeconds():
+		column.Precision = precision
+	case column.IsEnum():
+		column.Values = values
+	}
+	return column, nil
+}
+
+var sqlTypeRegexp = regexp.MustCompile(`(?i)^([a-z][a-z ]*?)\s*(?:\(\s*(\d+)\s*(?:,\s*(\d+)\s*)?\))?(\s+unsigned)?$`)
+
+// parseSQLType returns the column that has only the type of the `type' tag.
+// The dialect-neutral types are parsed into the attributes of the column,
+// and the other types are kept as is in the form of schema.RawType.
+func parseSQLType(s string) *schema.Column {
+	s = strings.TrimSpace(s)
+	if typ, values, ok := schema.ParseEnumType(s); ok {
+		return &schema.Column{Type: typ, Values: values}
+	}
+	m := sqlTypeRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return &schema.Column{Type: schema.RawType(s)}
+	}
+	c := &schema.Column{Type: strings.ToUpper(m[1])}
+	switch c.Type {
+	case "INTEGER":
+		c.Type = "INT"
+	case "BOOLEAN":
+		c.Type = "BOOL"
+	case "NUMERIC":
+		c.Type = "DECIMAL"
+	case "TINYINT":
+		if m[2] == "1" {
+			c.Type = "BOOL"
+		}
+	}
+	if !schema.IsNeutralType(c.Type) {
+		return &schema.Column{Type: schema.RawType(s)}
+	}
+	// the numbers are validated by the regexp.
+	n, _ := strconv.ParseUint(m[2], 10, 64)
+	switch {
+	case c.IsString(), c.IsBinary():
+		c.Size = n
+	case c.Type == "DECIMAL":
+		c.Precision = n
+		c.Scale, _ = strconv.ParseUint(m[3], 10, 64)
+	case c.HasFractionalSeconds():
+		c.Precision = n
+	}
+	c.Unsigned = m[4] != ""
+	return c
+}
+
+func decimalPrecision(precision uint64) uint64 {
+	if precision == 0 {
+		return defaultDecimalPrecision
+	}
+	return precision
+}
+
+// genericNullType returns T of sql.Null[T], or the empty string if name isn't
+// sql.Null[T].
+func genericNullType(name string) string {
+	if strings.HasPrefix(name, "sql.Null[") && strings.HasSuffix(name, "]") {
+		return strings.TrimSuffix(strings.TrimPrefix(name, "sql.Null["), "]")
+	}
+	return ""
+}
+
+// isNullableType reports whether the Go's type can be NULL.
+func isNullableType(name string) bool {
+	return strings.HasPrefix(name, "*") || strings.HasPrefix(name, "sql.Null") || name == "decimal.NullDecimal"
 }
 
-func (schema *columnSchema) fieldAST() (*ast.Field, error) {
-	types, err := schema.GoFieldTypes()
+// foreignKey returns the foreign key of the field in the table, or nil if
+// the field doesn't have the `fk' tag.
+func (f *field) foreignKey(tableName string) *schema.ForeignKey {
+	if f.ReferencedTable == "" {
+		return nil
+	}
+	return &schema.ForeignKey{
+		Name:              fmt.Sprintf("fk_%s_%s", tableName, f.Name),
+		Columns:           []string{f.Name},
+		ReferencedTable:   f.ReferencedTable,
+		ReferencedColumns: []string{f.ReferencedColumn},
+		OnDelete:          f.OnDelete,
+		OnUpdate:          f.OnUpdate,
+	}
+}
+
+// columnType returns the type of the schema from the Go's type.
+// sql.Null[T] is the nullable type of T.
+func columnType(name string) (typ string, unsigned, null bool, err error) {
+	if t := genericNullType(name); t != "" {
+		typ, unsigned, _, err = columnType(t)
+		return typ, unsigned, true, err
+	}
+	switch name {
+	case "string":
+		return "VARCHAR", false, false, nil
+	case "sql.NullString", "*string":
+		return "VARCHAR", false, true, nil
+	case "int", "int32":
+		return "INT", false, false, nil
+	case "*int", "*int32", "sql.NullInt32":
+		return "INT", false, true, nil
+	case "int8":
+		return "TINYINT", false, false, nil
+	case "*int8":
+		return "TINYINT", false, true, nil
+	case "bool":
+		return "BOOL", false, false, nil
+	case "*bool", "sql.NullBool":
+		return "BOOL", false, true, nil
+	case "int16":
+		return "SMALLINT", false, false, nil
+	case "*int16", "sql.NullInt16":
+		return "SMALLINT", false, true, nil
+	case "int64":
+		return "BIGINT", false, false, nil
+	case "sql.NullInt64", "*int64":
+		return "BIGINT", false, true, nil
+	case "uint", "uint32":
+		return "INT", true, false, nil
+	case "*uint", "*uint32":
+		return "INT", true, true, nil
+	case "uint8":
+		return "TINYINT", true, false, nil
+	case "*uint8", "sql.NullByte":
+		return "TINYINT", true, true, nil
+	case "uint16":
+		return "SMALLINT", true, false, nil
+	case "*uint16":
+		return "SMALLINT", true, true, nil
+	case "uint64":
+		return "BIGINT", true, false, nil
+	case "*uint64":
+		return "BIGINT", true, true, nil
+	case "float32":
+		return "FLOAT", false, false, nil
+	case "*float32":
+		return "FLOAT", false, true, nil
+	case "float64":
+		return "DOUBLE", false, false, nil
+	case "*float64", "sql.NullFloat64":
+		return "DOUBLE", false, true, nil
+	case "decimal.Decimal":
+		return "DECIMAL", false, false, nil
+	case "*decimal.Decimal", "decimal.NullDecimal":
+		return "DECIMAL", false, true, nil
+	case "[]byte", "[]uint8":
+		return "VARBINARY", false, false, nil
+	case "*[]byte", "*[]uint8":
+		return "VARBINARY", false, true, nil
+	case "json.RawMessage":
+		return "JSON", false, false, nil
+	case "*json.RawMessage":
+		return "JSON", false, true, nil
+	case "time.Time":
+		return "DATETIME", false, false, nil
+	case "*time.Time", "sql.NullTime":
+		return "DATETIME", false, true, nil
+	default:
+		return "", false, false, fmt.Errorf("unsupported type %s; use the `%s' tag", name, tagType)
+	}
+}
+
+// fieldAST returns the field of the column. The foreign key on the column
+// alone is expressed by the tags of the field. enumType is the named type of
+// the values of ENUM or SET column.
+func fieldAST(column *schema.Column, fk *schema.ForeignKey, enumType string, nullTypes bool) (*ast.Field, error) {
+	types, err := goFieldTypes(column)
+	if err != nil {
+		return nil, err
+	}
+	typ, err := goFieldType(column, nullTypes)
 	if err != nil {
 		return nil, err
 	}
+	if column.IsEnum() {
+		// the named type has the values as the constants.
+		typ = enumType
+		if column.Nullable {
+			typ = "*" + typ
+		}
+	}
 	field := &ast.Field{
 		Names: []*ast.Ident{
-			ast.NewIdent(toStructPublicFieldName(schema.ColumnName)),
+			ast.NewIdent(toStructPublicFieldName(column.Name)),
 		},
-		Type: ast.NewIdent(types[0]),
+		Type: ast.NewIdent(typ),
 	}
 	var tags []string
-	if schema.ColumnDefault.Valid {
-		tags = append(tags, tagDefault+":"+schema.ColumnDefault.String)
+	if column.Type == "SET" {
+		tags = append(tags, tagSet)
+	}
+	sqlType := typeTag(column)
+	if sqlType != "" {
+		tags = append(tags, tagType+":"+sqlType)
 	}
-	if schema.hasPrimaryKey() {
-		tags = append(tags, tagPrimaryKey)
+	if column.HasDefault() {
+		tags = append(tags, defaultTag(column, isStringType(types[0])))
+	}
+	if column.OnUpdate != "" {
+		tags = append(tags, tagOnUpdate+":"+column.OnUpdate)
+	}
+	if column.Generated != "" {
+		tags = append(tags, tagGenerated+":"+column.Generated)
+		if column.Stored {
+			tags = append(tags, tagStored)
+		}
 	}
-	if schema.hasAutoIncrement() {
+	if column.AutoIncrement {
 		tags = append(tags, tagAutoIncrement)
 	}
-	if schema.hasUniqueKey() {
+	if column.Unique {
 		tags = append(tags, tagUnique)
 	}
-	if schema.hasCharacterMaximumLength() {
-		tags = append(tags, fmt.Sprintf("%s:%d", tagSize, *schema.CharacterMaximumLength))
+	if (column.IsString() || column.IsBinary()) && column.Size > 0 && sqlType == "" {
+		tags = append(tags, fmt.Sprintf("%s:%d", tagSize, column.Size))
+	}
+	if column.Charset != "" {
+		tags = append(tags, tagCharset+":"+column.Charset)
+	}
+	if column.Collation != "" {
+		tags = append(tags, tagCollate+":"+column.Collation)
+	}
+	switch column.Type {
+	case "DATE", "TIMESTAMP", "TIME", "YEAR":
+		tags = append(tags, strings.ToLower(column.Type))
+	}
+	if column.HasFractionalSeconds() && column.Precision > 0 {
+		tags = append(tags, fmt.Sprintf("%s:%d", tagPrecision, column.Precision))
+	}
+	if fk != nil {
+		tags = append(tags, fmt.Sprintf("%s:%s.%s", tagForeignKey, fk.ReferencedTable, fk.ReferencedColumns[0]))
+		if action := foreignKeyAction(fk.OnDelete); action != "" {
+			tags = append(tags, tagOnDelete+":"+strings.ToLower(action))
+		}
+		if action := foreignKeyAction(fk.OnUpdate); action != "" {
+			tags = append(tags, tagOnUpdate+":"+strings.ToLower(action))
+		}
 	}
 	if len(tags) > 0 {
 		field.Tag = &ast.BasicLit{
@@ -61,134 +317,194 @@ func (schema *columnSchema) fieldAST() (*ast.Field, error) {
 			Value: fmt.Sprintf("`migu:\"%s\"`", strings.Join(tags, tagSeparater)),
 		}
 	}
-	if schema.ColumnComment != "" {
+	if column.Comment != "" {
 		field.Comment = &ast.CommentGroup{
 			List: []*ast.Comment{
-				{Text: "// " + schema.ColumnComment},
+				{Text: "// " + column.Comment},
 			},
 		}
 	}
 	return field, nil
 }
 
-func (schema *columnSchema) GoFieldTypes() ([]string, error) {
-	switch schema.DataType {
-	case "tinyint":
-		if schema.ColumnType == "tinyint(1)" {
-			if schema.isNullable() {
-				return []string{"*bool", "sql.NullBool"}, nil
+// defaultTag returns the `default' tag for the default value of the column.
+// The expression of the string field is the `defaultexpr' tag, and the
+// value of the other field that would be an expression is quoted.
+func defaultTag(column *schema.Column, str bool) string {
+	switch {
+	case column.DefaultExpr && str:
+		return tagDefaultExpr + ":" + column.Default
+	case column.DefaultExpr && schema.IsCurrentTimestamp(column.Default):
+		return tagDefault + ":" + column.Default
+	case column.DefaultExpr:
+		return tagDefault + ":(" + column.Default + ")"
+	case str:
+		return tagDefault + ":" + column.Default
+	}
+	if v, expr := parseDefaultTag(column.Default, false); expr || v != column.Default {
+		return tagDefault + ":'" + strings.Replace(column.Default, "'", "''", -1) + "'"
+	}
+	return tagDefault + ":" + column.Default
+}
+
+// typeTag returns the parameter of the `type' tag for the column that the
+// Go's type of the field can't express, or the empty string.
+func typeTag(column *schema.Column) string {
+	switch {
+	case !schema.IsNeutralType(column.Type):
+		return column.Type
+	case column.Type == "MEDIUMINT" && column.Unsigned:
+		return "MEDIUMINT UNSIGNED"
+	case column.Type == "MEDIUMINT":
+		return "MEDIUMINT"
+	case column.Type == "CHAR", column.Type == "BINARY":
+		return fmt.Sprintf("%s(%d)", column.Type, column.Size)
+	case column.Type == "DECIMAL":
+		// the field of DECIMAL is string that can't have the `precision'
+		// and `scale' tags.
+		return fmt.Sprintf("DECIMAL(%d,%d)", column.Precision, column.Scale)
+	case column.Type == "TEXT", column.Type == "MEDIUMTEXT", column.Type == "LONGTEXT":
+		// the TEXT types are kept as is, because the size of the string is in
+		// characters and the sizes of these are in bytes in MySQL. SQLite3
+		// reports TEXT without the size, including ENUM and SET that it
+		// doesn't have.
+		return column.Type
+	}
+	return ""
+}
+
+// goFieldType returns the Go's type of the field for the column. It's the
+// first one of goFieldTypes, or the Null type of database/sql for the
+// nullable column if nullTypes is true and the column has it.
+func goFieldType(column *schema.Column, nullTypes bool) (string, error) {
+	types, err := goFieldTypes(column)
+	if err != nil {
+		return "", err
+	}
+	if nullTypes && column.Nullable && !column.IsEnum() {
+		for _, typ := range types {
+			if strings.HasPrefix(typ, "sql.Null") {
+				return typ, nil
 			}
-			return []string{"bool"}, nil
 		}
-		if schema.isUnsigned() {
-			if schema.isNullable() {
-				return []string{"*uint8"}, nil
+	}
+	return types[0], nil
+}
+
+// goFieldTypes returns the Go's types of the column.
+// The first one is used to generate the field by default.
+func goFieldTypes(column *schema.Column) ([]string, error) {
+	switch column.Type {
+	case "BOOL":
+		if column.Nullable {
+			return []string{"*bool", "sql.NullBool"}, nil
+		}
+		return []string{"bool"}, nil
+	case "TINYINT":
+		if column.Unsigned {
+			if column.Nullable {
+				return []string{"*uint8", "sql.NullByte"}, nil
 			}
 			return []string{"uint8"}, nil
 		}
-		if schema.isNullable() {
+		if column.Nullable {
 			return []string{"*int8"}, nil
 		}
 		return []string{"int8"}, nil
-	case "smallint":
-		if schema.isUnsigned() {
-			if schema.isNullable() {
+	case "SMALLINT":
+		if column.Unsigned {
+			if column.Nullable {
 				return []string{"*uint16"}, nil
 			}
 			return []string{"uint16"}, nil
 		}
-		if schema.isNullable() {
-			return []string{"*int16"}, nil
+		if column.Nullable {
+			return []string{"*int16", "sql.NullInt16"}, nil
 		}
 		return []string{"int16"}, nil
-	case "mediumint", "int":
-		if schema.isUnsigned() {
-			if schema.isNullable() {
+	case "MEDIUMINT", "INT":
+		if column.Unsigned {
+			if column.Nullable {
 				return []string{"*uint", "*uint32"}, nil
 			}
 			return []string{"uint", "uint32"}, nil
 		}
-		if schema.isNullable() {
-			return []string{"*int", "*int32"}, nil
+		if column.Nullable {
+			return []string{"*int", "*int32", "sql.NullInt32"}, nil
 		}
 		return []string{"int", "int32"}, nil
-	case "bigint":
-		if schema.isUnsigned() {
-			if schema.isNullable() {
+	case "BIGINT":
+		if column.Unsigned {
+			if column.Nullable {
 				return []string{"*uint64"}, nil
 			}
 			return []string{"uint64"}, nil
 		}
-		if schema.isNullable() {
+		if column.Nullable {
 			return []string{"*int64", "sql.NullInt64"}, nil
 		}
 		return []string{"int64"}, nil
-	case "varchar", "text", "mediumtext", "longtext":
-		if schema.isNullable() {
+	case "CHAR", "VARCHAR", "TEXT", "MEDIUMTEXT", "LONGTEXT":
+		if column.Nullable {
 			return []string{"*string", "sql.NullString"}, nil
 		}
 		return []string{"string"}, nil
-	case "datetime":
-		if schema.isNullable() {
-			return []string{"*time.Time"}, nil
+	case "DATETIME", "TIMESTAMP", "DATE":
+		if column.Nullable {
+			return []string{"*time.Time", "sql.NullTime"}, nil
 		}
 		return []string{"time.Time"}, nil
-	case "double":
-		if schema.isNullable() {
+	case "BINARY", "VARBINARY", "BLOB", "MEDIUMBLOB", "LONGBLOB":
+		if column.Nullable {
+			return []string{"*[]byte"}, nil
+		}
+		return []string{"[]byte"}, nil
+	case "ENUM", "SET":
+		// the underlying type of the named type for the values.
+		if column.Nullable {
+			return []string{"*string", "sql.NullString"}, nil
+		}
+		return []string{"string"}, nil
+	case "JSON":
+		if column.Nullable {
+			return []string{"*json.RawMessage"}, nil
+		}
+		return []string{"json.RawMessage"}, nil
+	case "TIME":
+		// TIME is the elapsed time that can be negative or more than a day.
+		if column.Nullable {
+			return []string{"*string", "sql.NullString"}, nil
+		}
+		return []string{"string"}, nil
+	case "YEAR":
+		if column.Nullable {
+			return []string{"*int16", "sql.NullInt16"}, nil
+		}
+		return []string{"int16"}, nil
+	case "DOUBLE":
+		if column.Nullable {
 			return []string{"*float64", "sql.NullFloat64"}, nil
 		}
 		return []string{"float64"}, nil
-	case "float":
-		if schema.isNullable() {
-			return []string{"*float32", "sql.NullFloat32"}, nil
+	case "DECIMAL":
+		// string keeps the exact value without the third-party library.
+		if column.Nullable {
+			return []string{"*string", "sql.NullString", "decimal.NullDecimal"}, nil
+		}
+		return []string{"string", "decimal.Decimal"}, nil
+	case "FLOAT":
+		if column.Nullable {
+			return []string{"*float32", "sql.Null[float32]"}, nil
 		}
 		return []string{"float32"}, nil
 	default:
-		return nil, fmt.Errorf("BUG: unexpected data type: %s", schema.DataType)
-	}
-}
-
-func (schema *columnSchema) isUnsigned() bool {
-	return strings.Contains(schema.ColumnType, "unsigned")
-}
-
-func (schema *columnSchema) isNullable() bool {
-	return strings.ToUpper(schema.IsNullable) == "YES"
-}
-
-func (schema *columnSchema) hasPrimaryKey() bool {
-	return schema.ColumnKey == "PRI" && strings.ToUpper(schema.IndexName) == "PRIMARY"
-}
-
-func (schema *columnSchema) hasAutoIncrement() bool {
-	return schema.Extra == "auto_increment"
-}
-
-func (schema *columnSchema) hasUniqueKey() bool {
-	return schema.ColumnKey != "" && schema.IndexName != "" && !schema.hasPrimaryKey()
-}
-
-func (schema *columnSchema) hasCharacterMaximumLength() bool {
-	return schema.CharacterMaximumLength != nil
-}
-
-func (schema *columnSchema) HasDifference(newColumn *field) bool {
-	goTypes, err := schema.GoFieldTypes()
-	if err != nil {
-		panic(err)
-	}
-	if !inStrings(goTypes, newColumn.Type) {
-		return true
-	}
-
-	fieldAST, err := schema.fieldAST()
-	if err != nil {
-		panic(err)
-	}
-	currentColumn, err := newField(newColumn.Type, fieldAST)
-	currentColumn.Name = newColumn.Name
-	if err != nil {
-		panic(err)
+		if !schema.IsNeutralType(column.Type) {
+			// the raw type is specified by the `type' tag.
+			if column.Nullable {
+				return []string{"*string", "sql.NullString"}, nil
+			}
+			return []string{"string"}, nil
+		}
+		return nil, fmt.Errorf("BUG: unexpected data type: %s", column.Type)
 	}
-	return !reflect.DeepEqual(currentColumn, newColumn)
 }
@@ -0,0 +1,613 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/astronoka/migu/schema"
+)
+
+// DDLParser is implemented by the dialects that can read the schema from
+// the DDL instead of the database.
+type DDLParser interface {
+	// ParseDDL returns the tables defined by CREATE TABLE and CREATE INDEX
+	// statements in src ordered by the name. The other statements are
+	// ignored.
+	ParseDDL(src string) ([]*schema.Table, error)
+}
+
+type ddlTokenKind int
+
+const (
+	ddlIdent ddlTokenKind = iota
+	ddlQuotedIdent
+	ddlString
+	ddlNumber
+	ddlSymbol
+)
+
+type ddlToken struct {
+	kind  ddlTokenKind
+	value string
+}
+
+// is reports whether the token is the keyword that is one of the words.
+func (t ddlToken) is(words ...string) bool {
+	if t.kind != ddlIdent {
+		return false
+	}
+	for _, word := range words {
+		if strings.EqualFold(t.value, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenizeDDL splits src into the tokens. The comments are skipped,
+// including the conditional comments of MySQL such as /*!40101 ... */.
+func tokenizeDDL(src string) ([]ddlToken, error) {
+	var tokens []ddlToken
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '#' || strings.HasPrefix(src[i:], "--"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				return tokens, nil
+			}
+			i += end + 1
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i += end + 4
+		case c == '`' || c == '"' || c == '\'':
+			value, n, err := unquoteDDL(src[i:])
+			if err != nil {
+				return nil, err
+			}
+			kind := ddlQuotedIdent
+			if c == '\'' {
+				kind = ddlString
+			}
+			tokens = append(tokens, ddlToken{kind: kind, value: value})
+			i += n
+		case isDDLDigit(c):
+			n := 1
+			for n < len(src[i:]) && (isDDLDigit(src[i+n]) || src[i+n] == '.') {
+				n++
+			}
+			tokens = append(tokens, ddlToken{kind: ddlNumber, value: src[i : i+n]})
+			i += n
+		case isDDLIdentChar(c):
+			n := 1
+			for n < len(src[i:]) && isDDLIdentChar(src[i+n]) {
+				n++
+			}
+			tokens = append(tokens, ddlToken{kind: ddlIdent, value: src[i : i+n]})
+			i += n
+		default:
+			tokens = append(tokens, ddlToken{kind: ddlSymbol, value: string(c)})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// unquoteDDL returns the content of the quoted token at the beginning of s
+// and the length of the token.
+func unquoteDDL(s string) (string, int, error) {
+	quote := s[0]
+	var buf []byte
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && quote == '\'' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				buf = append(buf, '\n')
+			case 't':
+				buf = append(buf, '\t')
+			case 'r':
+				buf = append(buf, '\r')
+			case '0':
+				buf = append(buf, 0)
+			default:
+				buf = append(buf, s[i])
+			}
+		case c == quote && i+1 < len(s) && s[i+1] == quote:
+			buf = append(buf, quote)
+			i++
+		case c == quote:
+			return string(buf), i + 1, nil
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated quoted string: %s", s)
+}
+
+func isDDLDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isDDLIdentChar(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || isDDLDigit(c) || c == '_' || c == '$' || c >= 0x80
+}
+
+// ddlParser parses CREATE TABLE and CREATE INDEX statements.
+// setType sets the type of the column from the declared type in lower case
+// such as "varchar(255)" and "int(10) unsigned".
+type ddlParser struct {
+	setType func(c *schema.Column, typ string) error
+	tables  []*schema.Table
+}
+
+func (p *ddlParser) parse(src string) ([]*schema.Table, error) {
+	tokens, err := tokenizeDDL(src)
+	if err != nil {
+		return nil, err
+	}
+	for len(tokens) > 0 {
+		end := 0
+		for end < len(tokens) && !(tokens[end].kind == ddlSymbol && tokens[end].value == ";") {
+			end++
+		}
+		if err := p.parseStatement(tokens[:end]); err != nil {
+			return nil, err
+		}
+		if end == len(tokens) {
+			break
+		}
+		tokens = tokens[end+1:]
+	}
+	return p.tables, nil
+}
+
+func (p *ddlParser) parseStatement(tokens []ddlToken) error {
+	if len(tokens) < 2 || !tokens[0].is("CREATE") {
+		return nil
+	}
+	tokens = tokens[1:]
+	if tokens[0].is("TEMPORARY") {
+		tokens = tokens[1:]
+	}
+	switch {
+	case tokens[0].is("TABLE"):
+		return p.parseCreateTable(tokens[1:])
+	case tokens[0].is("INDEX"):
+		return p.parseCreateIndex(tokens[1:], false)
+	case tokens[0].is("UNIQUE") && len(tokens) > 1 && tokens[1].is("INDEX"):
+		return p.parseCreateIndex(tokens[2:], true)
+	}
+	return nil
+}
+
+func (p *ddlParser) parseCreateTable(tokens []ddlToken) error {
+	tokens = skipIfNotExists(tokens)
+	name, tokens := parseDDLName(tokens)
+	if name == "" || len(tokens) == 0 || tokens[0].value != "(" {
+		// CREATE TABLE ... LIKE or AS SELECT.
+		return nil
+	}
+	end := matchDDLParen(tokens, 0)
+	if end < 0 {
+		return fmt.Errorf("unterminated definitions of table %s", name)
+	}
+	table := &schema.Table{Name: name}
+	var pk []string
+	for _, def := range splitDDLList(tokens[1:end]) {
+		if len(def) == 0 {
+			continue
+		}
+		if def[0].kind == ddlIdent && def[0].is("CONSTRAINT", "PRIMARY", "UNIQUE", "KEY", "INDEX", "FOREIGN", "CHECK", "FULLTEXT", "SPATIAL") {
+			parseDDLTableConstraint(table, def)
+			continue
+		}
+		column, primaryKey, err := p.parseColumn(def)
+		if err != nil {
+			return fmt.Errorf("table %s: %v", name, err)
+		}
+		table.Columns = append(table.Columns, column)
+		if primaryKey {
+			pk = append(pk, column.Name)
+		}
+	}
+	if len(pk) > 0 && table.PrimaryKey() == nil {
+		table.Indexes = append([]*schema.Index{{
+			Name:    schema.PrimaryKeyName,
+			Unique:  true,
+			Columns: pk,
+		}}, table.Indexes...)
+	}
+	parseDDLTableOptions(table, tokens[end+1:])
+	p.tables = append(p.tables, table)
+	return nil
+}
+
+// parseColumn parses the column definition.
+func (p *ddlParser) parseColumn(def []ddlToken) (column *schema.Column, primaryKey bool, err error) {
+	column = &schema.Column{
+		Name:     def[0].value,
+		Nullable: true,
+	}
+	i := 1
+	var words []string
+	for ; i < len(def) && def[i].kind == ddlIdent && !isDDLColumnKeyword(def[i]); i++ {
+		words = append(words, strings.ToLower(def[i].value))
+	}
+	typ := strings.Join(words, " ")
+	if i < len(def) && def[i].value == "(" {
+		end := matchDDLParen(def, i)
+		if end < 0 {
+			return nil, false, fmt.Errorf("unterminated type of column %s", column.Name)
+		}
+		var args []string
+		for _, arg := range splitDDLList(def[i+1 : end]) {
+			args = append(args, ddlText(arg))
+		}
+		typ += "(" + strings.Join(args, ",") + ")"
+		i = end + 1
+	}
+	for ; i < len(def) && def[i].is("UNSIGNED", "SIGNED", "ZEROFILL"); i++ {
+		if def[i].is("UNSIGNED") {
+			typ += " unsigned"
+		}
+	}
+	if err := p.setType(column, typ); err != nil {
+		return nil, false, err
+	}
+	for i < len(def) {
+		t := def[i]
+		i++
+		switch {
+		case t.is("NOT") && i < len(def) && def[i].is("NULL"):
+			column.Nullable = false
+			i++
+		case t.is("DEFAULT") && i < len(def):
+			column.Default, column.DefaultExpr, i = parseDDLDefault(def, i)
+		case t.is("ON") && i+1 < len(def) && def[i].is("UPDATE"):
+			column.OnUpdate, _, i = parseDDLDefault(def, i+1)
+		case t.is("GENERATED"):
+			if i < len(def) && def[i].is("ALWAYS") {
+				i++
+			}
+		case t.is("AS") && i < len(def) && def[i].value == "(":
+			end := matchDDLParen(def, i)
+			if end < 0 {
+				return nil, false, fmt.Errorf("unterminated expression of column %s", column.Name)
+			}
+			column.Generated = schema.TrimExpr(ddlText(def[i+1 : end]))
+			i = end + 1
+			// VIRTUAL is the default, and MariaDB calls STORED PERSISTENT.
+			if i < len(def) && def[i].is("VIRTUAL", "STORED", "PERSISTENT") {
+				column.Stored = !def[i].is("VIRTUAL")
+				i++
+			}
+		case t.is("AUTO_INCREMENT", "AUTOINCREMENT"):
+			column.AutoIncrement = true
+		case t.is("PRIMARY"):
+			primaryKey = true
+			if i < len(def) && def[i].is("KEY") {
+				i++
+			}
+		case t.is("KEY"):
+			primaryKey = true
+		case t.is("UNIQUE"):
+			column.Unique = true
+			if i < len(def) && def[i].is("KEY") {
+				i++
+			}
+		case t.is("COMMENT") && i < len(def):
+			column.Comment = def[i].value
+			i++
+		case t.is("CHARACTER") && i+1 < len(def):
+			column.Charset = def[i+1].value // CHARACTER SET name
+			i += 2
+		case t.is("CHARSET") && i < len(def):
+			column.Charset = def[i].value
+			i++
+		case t.is("COLLATE") && i < len(def):
+			column.Collation = def[i].value
+			i++
+		case t.is("CONSTRAINT") && i < len(def):
+			i++
+		case t.is("REFERENCES"):
+			i = len(def)
+		case t.value == "(":
+			if end := matchDDLParen(def, i-1); end > 0 {
+				i = end + 1
+			}
+		}
+	}
+	return column, primaryKey, nil
+}
+
+func (p *ddlParser) parseCreateIndex(tokens []ddlToken, unique bool) error {
+	tokens = skipIfNotExists(tokens)
+	index := &schema.Index{Unique: unique}
+	index.Name, tokens = parseDDLName(tokens)
+	if len(tokens) == 0 || !tokens[0].is("ON") {
+		return fmt.Errorf("invalid CREATE INDEX statement for %s", index.Name)
+	}
+	tableName, tokens := parseDDLName(tokens[1:])
+	table := p.table(tableName)
+	if table == nil {
+		return fmt.Errorf("CREATE INDEX %s: table %s is not defined", index.Name, tableName)
+	}
+	index.Columns = parseDDLIndexColumns(tokens)
+	table.Indexes = append(table.Indexes, index)
+	return nil
+}
+
+func (p *ddlParser) table(name string) *schema.Table {
+	for _, table := range p.tables {
+		if table.Name == name {
+			return table
+		}
+	}
+	return nil
+}
+
+// parseDDLTableConstraint parses the definition of the primary key and the
+// index and the foreign key in CREATE TABLE. The unnamed index has the empty
+// name. The other constraints are ignored.
+func parseDDLTableConstraint(table *schema.Table, def []ddlToken) {
+	var name string
+	if def[0].is("CONSTRAINT") {
+		def = def[1:]
+		if len(def) > 0 && !def[0].is("PRIMARY", "UNIQUE", "FOREIGN", "CHECK") {
+			name, def = def[0].value, def[1:]
+		}
+	}
+	if len(def) == 0 {
+		return
+	}
+	index := &schema.Index{}
+	switch {
+	case def[0].is("PRIMARY"):
+		index.Name = schema.PrimaryKeyName
+		index.Unique = true
+	case def[0].is("UNIQUE"):
+		index.Name = name
+		index.Unique = true
+		if len(def) > 1 && def[1].is("KEY", "INDEX") {
+			def = def[1:]
+		}
+	case def[0].is("KEY", "INDEX"):
+	case def[0].is("FOREIGN"):
+		table.ForeignKeys = append(table.ForeignKeys, parseDDLForeignKey(name, def[1:]))
+		return
+	default:
+		return
+	}
+	def = def[1:]
+	if len(def) > 0 && def[0].is("KEY") {
+		def = def[1:]
+	}
+	if len(def) > 0 && def[0].value != "(" && !def[0].is("USING") {
+		index.Name, def = def[0].value, def[1:]
+	}
+	index.Columns = parseDDLIndexColumns(def)
+	table.Indexes = append(table.Indexes, index)
+}
+
+// parseDDLForeignKey parses the foreign key after FOREIGN.
+func parseDDLForeignKey(name string, def []ddlToken) *schema.ForeignKey {
+	fk := &schema.ForeignKey{Name: name}
+	ref := len(def)
+	for i, t := range def {
+		if t.is("REFERENCES") {
+			ref = i
+			break
+		}
+	}
+	fk.Columns = parseDDLIndexColumns(def[:ref])
+	if ref == len(def) {
+		return fk
+	}
+	var rest []ddlToken
+	fk.ReferencedTable, rest = parseDDLName(def[ref+1:])
+	fk.ReferencedColumns = parseDDLIndexColumns(rest)
+	if len(rest) > 0 && rest[0].value == "(" {
+		if end := matchDDLParen(rest, 0); end > 0 {
+			rest = rest[end+1:]
+		}
+	}
+	for i := 0; i+2 < len(rest); i++ {
+		if !rest[i].is("ON") {
+			continue
+		}
+		action := strings.ToUpper(rest[i+2].value)
+		if rest[i+2].is("SET", "NO") && i+3 < len(rest) {
+			action += " " + strings.ToUpper(rest[i+3].value)
+		}
+		switch {
+		case rest[i+1].is("DELETE"):
+			fk.OnDelete = action
+		case rest[i+1].is("UPDATE"):
+			fk.OnUpdate = action
+		}
+	}
+	return fk
+}
+
+// parseDDLTableOptions parses the table options after the definitions.
+func parseDDLTableOptions(table *schema.Table, tokens []ddlToken) {
+	value := func(i int) (string, int) {
+		if i < len(tokens) && tokens[i].value == "=" {
+			i++
+		}
+		if i < len(tokens) {
+			return tokens[i].value, i + 1
+		}
+		return "", i
+	}
+	for i := 0; i < len(tokens); {
+		t := tokens[i]
+		i++
+		switch {
+		case t.is("ENGINE"):
+			table.Option.Engine, i = value(i)
+		case t.is("CHARSET"):
+			table.Option.Charset, i = value(i)
+		case t.is("CHARACTER") && i < len(tokens) && tokens[i].is("SET"):
+			table.Option.Charset, i = value(i + 1)
+		case t.is("COLLATE"):
+			table.Option.Collation, i = value(i)
+		case t.is("COMMENT"):
+			table.Option.Comment, i = value(i)
+		}
+	}
+}
+
+// parseDDLIndexColumns returns the column names in the first parentheses.
+// The prefix lengths and the orders of the columns are ignored.
+func parseDDLIndexColumns(tokens []ddlToken) []string {
+	columns := []string{}
+	for i, t := range tokens {
+		if t.value != "(" || t.kind != ddlSymbol {
+			continue
+		}
+		end := matchDDLParen(tokens, i)
+		if end < 0 {
+			break
+		}
+		for _, part := range splitDDLList(tokens[i+1 : end]) {
+			if len(part) > 0 {
+				columns = append(columns, part[0].value)
+			}
+		}
+		break
+	}
+	return columns
+}
+
+// parseDDLDefault returns the default value at tokens[i], whether it's an
+// expression, and the index of the next token. DEFAULT NULL means that the
+// column has no default value. The expression is in the form of
+// schema.NormalizeDefaultExpr.
+func parseDDLDefault(tokens []ddlToken, i int) (string, bool, int) {
+	t := tokens[i]
+	switch {
+	case t.is("NULL"):
+		return "", false, i + 1
+	case t.is("TRUE", "FALSE"):
+		return t.value, false, i + 1
+	case t.kind == ddlSymbol && (t.value == "-" || t.value == "+") && i+1 < len(tokens):
+		return strings.TrimPrefix(t.value, "+") + tokens[i+1].value, false, i + 2
+	case t.kind == ddlSymbol && t.value == "(":
+		if end := matchDDLParen(tokens, i); end > 0 {
+			return schema.NormalizeDefaultExpr(ddlText(tokens[i+1 : end])), true, end + 1
+		}
+	case t.kind == ddlIdent && i+1 < len(tokens) && tokens[i+1].value == "(":
+		// function call such as CURRENT_TIMESTAMP(3).
+		if end := matchDDLParen(tokens, i+1); end > 0 {
+			return schema.NormalizeDefaultExpr(ddlText(tokens[i : end+1])), true, end + 1
+		}
+	case t.kind == ddlIdent:
+		// keyword such as CURRENT_TIMESTAMP.
+		return schema.NormalizeDefaultExpr(t.value), true, i + 1
+	}
+	return t.value, false, i + 1
+}
+
+// parseDDLName returns the name at the beginning of tokens and the rest.
+// The qualifier such as the database name is removed.
+func parseDDLName(tokens []ddlToken) (string, []ddlToken) {
+	if len(tokens) == 0 || (tokens[0].kind != ddlIdent && tokens[0].kind != ddlQuotedIdent) {
+		return "", tokens
+	}
+	name := tokens[0].value
+	tokens = tokens[1:]
+	for len(tokens) > 1 && tokens[0].value == "." {
+		name = tokens[1].value
+		tokens = tokens[2:]
+	}
+	return name, tokens
+}
+
+func skipIfNotExists(tokens []ddlToken) []ddlToken {
+	if len(tokens) > 2 && tokens[0].is("IF") && tokens[1].is("NOT") && tokens[2].is("EXISTS") {
+		return tokens[3:]
+	}
+	return tokens
+}
+
+// matchDDLParen returns the index of the parenthesis that closes
+// tokens[start], or -1 if not found.
+func matchDDLParen(tokens []ddlToken, start int) int {
+	depth := 0
+	for i := start; i < len(tokens); i++ {
+		if tokens[i].kind != ddlSymbol {
+			continue
+		}
+		switch tokens[i].value {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitDDLList splits tokens by the commas outside of the parentheses.
+func splitDDLList(tokens []ddlToken) [][]ddlToken {
+	var (
+		list  [][]ddlToken
+		depth int
+		start int
+	)
+	for i, t := range tokens {
+		if t.kind != ddlSymbol {
+			continue
+		}
+		switch t.value {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case ",":
+			if depth == 0 {
+				list = append(list, tokens[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(list, tokens[start:])
+}
+
+// ddlText returns the text of the tokens. The strings are quoted again, and
+// the adjacent words are separated by a space such as "INTERVAL 1 DAY".
+func ddlText(tokens []ddlToken) string {
+	var buf []string
+	for i, t := range tokens {
+		if i > 0 && isDDLWord(t) && isDDLWord(tokens[i-1]) {
+			buf = append(buf, " ")
+		}
+		if t.kind == ddlString {
+			buf = append(buf, "'"+strings.Replace(t.value, "'", "''", -1)+"'")
+		} else {
+			buf = append(buf, t.value)
+		}
+	}
+	return strings.Join(buf, "")
+}
+
+func isDDLWord(t ddlToken) bool {
+	return t.kind == ddlIdent || t.kind == ddlQuotedIdent || t.kind == ddlNumber
+}
+
+func isDDLColumnKeyword(t ddlToken) bool {
+	return t.is("NOT", "NULL", "DEFAULT", "PRIMARY", "KEY", "UNIQUE", "AUTO_INCREMENT", "AUTOINCREMENT",
+		"COMMENT", "CHARACTER", "CHARSET", "COLLATE", "CONSTRAINT", "REFERENCES", "CHECK", "GENERATED", "AS",
+		"ON", "UNSIGNED", "SIGNED", "ZEROFILL")
+}
@@ -1,8 +1,73 @@
 package dialect
 
+import (
+	"database/sql"
+
+	"github.com/astronoka/migu/schema"
+)
+
 type Dialect interface {
-	ColumnType(name string, size uint64, autoIncrement bool) (typ string, null bool)
+	Inspector
+
+	// ColumnType returns the type of the column in the dialect.
+	ColumnType(c *schema.Column) string
 	Quote(s string) string
 	QuoteString(s string) string
-	AutoIncrement() string
+
+	// CreateTable returns the statements to create the table.
+	CreateTable(t *schema.Table) []string
+
+	// DropTable returns the statements to drop the table.
+	DropTable(t *schema.Table) []string
+
+	// RenameTable returns the statements to rename the table to name.
+	RenameTable(t *schema.Table, name string) []string
+
+	// AlterTable returns the statements to alter the table from
+	// diff.Current to diff.Expected.
+	// The changes that the database can't store (e.g. the comment of the
+	// column in SQLite3 and ON UPDATE in the dialects other than MySQL) may
+	// be ignored.
+	AlterTable(diff *schema.TableDiff) []string
+}
+
+// Inspector inspects the schema of the current database.
+type Inspector interface {
+	// Tables returns the tables in the current database ordered by the name.
+	Tables(db *sql.DB) ([]*schema.Table, error)
+}
+
+// Rebuilder is implemented by the dialect that rebuilds the table instead of
+// altering it for some changes.
+// If NeedsRebuild reports true, AlterTable rebuilds the table to apply all
+// the changes at once, and the changes can't be applied separately.
+type Rebuilder interface {
+	NeedsRebuild(diff *schema.TableDiff) bool
+}
+
+// Migrator is implemented by the dialect that needs to prepare the
+// connection out of the transaction of the migration.
+type Migrator interface {
+	// Migrate calls exec for each query in a transaction, and commits it if
+	// all of them succeed.
+	Migrate(db *sql.DB, queries []string, exec func(tx *sql.Tx, query string) error) error
+}
+
+// CharsetInspector is implemented by the dialect that the type of the column
+// depends on the character set, in order to create the table in the default
+// character set of the database.
+type CharsetInspector interface {
+	// DefaultCharset returns the default character set of the current
+	// database.
+	DefaultCharset(db *sql.DB) (string, error)
+}
+
+// Collator is implemented by the dialect that supports the character set and
+// the collation of the column.
+type Collator interface {
+	// ColumnCollation returns the character set and the collation of the
+	// column in the table. The empty ones of the column are the default of
+	// the table. The collation is empty if the column has only the character
+	// set, and it means the default collation of the character set.
+	ColumnCollation(t *schema.Table, c *schema.Column) (charset, collation string)
 }
@@ -1,70 +1,106 @@
 package dialect
 
 import (
+	"database/sql"
 	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/astronoka/migu/schema"
 )
 
+// mysqlLOBSizes is the maximum length of the TEXT and BLOB types.
+var mysqlLOBSizes = map[string]uint64{
+	"tinytext":   255,
+	"text":       65535,
+	"mediumtext": 16777215,
+	"longtext":   4294967295,
+	"tinyblob":   255,
+	"blob":       65535,
+	"mediumblob": 16777215,
+	"longblob":   4294967295,
+}
+
+// mysqlCharsetMaxLengths is the maximum length in bytes of a character of
+// the character sets that aren't 4 bytes.
+var mysqlCharsetMaxLengths = map[string]uint64{
+	"ascii":   1,
+	"binary":  1,
+	"latin1":  1,
+	"latin2":  1,
+	"cp1250":  1,
+	"cp1251":  1,
+	"cp1256":  1,
+	"cp1257":  1,
+	"ucs2":    2,
+	"big5":    2,
+	"cp932":   2,
+	"euckr":   2,
+	"gb2312":  2,
+	"gbk":     2,
+	"sjis":    2,
+	"eucjpms": 3,
+	"ujis":    3,
+	"utf8":    3,
+	"utf8mb3": 3,
+}
+
+// mysqlOnUpdateRegexp matches ON UPDATE in EXTRA of information_schema.COLUMNS,
+// such as "DEFAULT_GENERATED on update CURRENT_TIMESTAMP(6)".
+var mysqlOnUpdateRegexp = regexp.MustCompile(`(?i)\bon update (\S+)`)
+
+// mysqlGeneratedRegexp matches the generated column in EXTRA of
+// information_schema.COLUMNS, such as "VIRTUAL GENERATED". MariaDB reports
+// the stored one as "PERSISTENT GENERATED" in some versions.
+var mysqlGeneratedRegexp = regexp.MustCompile(`(?i)\b(VIRTUAL|STORED|PERSISTENT) GENERATED\b`)
+
+// mysqlDefaultCharset is the character set that is assumed if neither the
+// column nor the table has the character set.
+const mysqlDefaultCharset = "utf8mb4"
+
 type MySQL struct {
 }
 
-func (d *MySQL) ColumnType(name string, size uint64, autoIncrement bool) (typ string, null bool) {
-	switch name {
-	case "string":
-		return d.varchar(size), false
-	case "sql.NullString", "*string":
-		return d.varchar(size), true
-	case "int", "int32":
-		return "INT", false
-	case "*int", "*int32":
-		return "INT", true
-	case "int8":
-		return "TINYINT", false
-	case "*int8":
-		return "TINYINT", true
-	case "bool":
-		return "BOOL", false
-	case "*bool", "sql.NullBool":
-		return "BOOL", true
-	case "int16":
-		return "SMALLINT", false
-	case "*int16":
-		return "SMALLINT", true
-	case "int64":
-		return "BIGINT", false
-	case "sql.NullInt64", "*int64":
-		return "BIGINT", true
-	case "uint", "uint32":
-		return "INT UNSIGNED", false
-	case "*uint", "*uint32":
-		return "INT UNSIGNED", true
-	case "uint8":
-		return "TINYINT UNSIGNED", false
-	case "*uint8":
-		return "TINYINT UNSIGNED", true
-	case "uint16":
-		return "SMALLINT UNSIGNED", false
-	case "*uint16":
-		return "SMALLINT UNSIGNED", true
-	case "uint64":
-		return "BIGINT UNSIGNED", false
-	case "*uint64":
-		return "BIGINT UNSIGNED", true
-	case "float32":
-		return "FLOAT", false
-	case "*float32", "sql.NullFloat32":
-		return "FLOAT", true
-	case "float64":
-		return "DOUBLE", false
-	case "*float64", "sql.NullFloat64":
-		return "DOUBLE", true
-	case "time.Time":
-		return "DATETIME", false
-	case "*time.Time":
-		return "DATETIME", true
-	default:
-		return "VARCHAR(255)", true
+// ColumnType returns the type of the column in the table that has the
+// default character set.
+func (d *MySQL) ColumnType(c *schema.Column) string {
+	return d.columnType(&schema.Table{}, c)
+}
+
+// columnType returns the type of the column in the table. The maximum length
+// of VARCHAR depends on the character set of the column.
+func (d *MySQL) columnType(t *schema.Table, c *schema.Column) string {
+	switch c.Type {
+	case "VARCHAR":
+		charset, _ := d.ColumnCollation(t, c)
+		return d.varchar(c.Size, charset)
+	case "CHAR":
+		return fmt.Sprintf("CHAR(%d)", c.Size)
+	case "VARBINARY":
+		return d.varbinary(c.Size)
+	case "BINARY":
+		return fmt.Sprintf("BINARY(%d)", c.Size)
+	case "DECIMAL":
+		return fmt.Sprintf("DECIMAL(%d,%d)", c.Precision, c.Scale)
+	case "ENUM", "SET":
+		values := make([]string, len(c.Values))
+		for i, v := range c.Values {
+			values[i] = d.QuoteString(v)
+		}
+		return fmt.Sprintf("%s(%s)", c.Type, strings.Join(values, ","))
+	case "DATETIME", "TIMESTAMP", "TIME":
+		if c.Precision > 0 {
+			return fmt.Sprintf("%s(%d)", c.Type, c.Precision)
+		}
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "BIGINT", "FLOAT", "DOUBLE":
+		if c.Unsigned {
+			return c.Type + " UNSIGNED"
+		}
 	}
+	return c.Type
 }
 
 func (d *MySQL) Quote(s string) string {
@@ -75,21 +111,704 @@ func (d *MySQL) QuoteString(s string) string {
 	return fmt.Sprintf("'%s'", strings.Replace(s, "'", "''", -1))
 }
 
-func (d *MySQL) AutoIncrement() string {
-	return "AUTO_INCREMENT"
-}
-
-func (d *MySQL) varchar(size uint64) string {
+// varchar returns VARCHAR or the TEXT type that can store the size of
+// characters. The maximum length of VARCHAR is 64KB in bytes, such as 16383
+// characters in utf8mb4 and 21844 characters in utf8, and the maximum
+// lengths of the TEXT types are in bytes as well.
+func (d *MySQL) varchar(size uint64, charset string) string {
 	if size == 0 {
 		size = 255 // default.
 	}
+	if charset == "" {
+		charset = mysqlDefaultCharset
+	}
+	maxLength, exist := mysqlCharsetMaxLengths[charset]
+	if !exist {
+		maxLength = 4
+	}
+	// the length of the value takes 2 bytes, and NULL takes 1 byte.
+	maxSize := 65532 / maxLength
 	switch {
-	case size < 21846:
+	case size <= maxSize:
 		return fmt.Sprintf("VARCHAR(%d)", size)
-	case size <= 65535: // approximate 64KB.
+	case size*maxLength <= 65535: // 64KB.
 		return "TEXT"
-	case size < 1<<24: // 16MB.
+	case size*maxLength < 1<<24: // 16MB.
 		return "MEDIUMTEXT"
 	}
 	return "LONGTEXT"
 }
+
+// varbinary returns VARBINARY or the BLOB type that can store the size of
+// bytes. VARBINARY of zero size is BLOB.
+func (d *MySQL) varbinary(size uint64) string {
+	switch {
+	case size == 0:
+		return "BLOB"
+	case size <= 65532: // the maximum row size minus the length and the NULL flag.
+		return fmt.Sprintf("VARBINARY(%d)", size)
+	case size <= 65535:
+		return "BLOB"
+	case size < 1<<24: // 16MB.
+		return "MEDIUMBLOB"
+	}
+	return "LONGBLOB"
+}
+
+// see: https://dev.mysql.com/doc/refman/5.6/en/create-table.html
+func (d *MySQL) CreateTable(t *schema.Table) []string {
+	pk := inlinePrimaryKey(t)
+	definitions := make([]string, 0, len(t.Columns)+len(t.Indexes)+len(t.ForeignKeys))
+	for _, c := range t.Columns {
+		definitions = append(definitions, d.columnSQL(t, c, c.Name == pk, c.Unique))
+	}
+	for _, index := range t.Indexes {
+		if index.IsPrimaryKey() && pk != "" {
+			continue
+		}
+		definitions = append(definitions, d.indexSQL(index))
+	}
+	for _, fk := range t.ForeignKeys {
+		definitions = append(definitions, foreignKeySQL(d, fk))
+	}
+	return []string{fmt.Sprintf(`CREATE TABLE %s (
+  %s
+)`, d.Quote(t.Name), strings.Join(definitions, ", "))}
+}
+
+func (d *MySQL) DropTable(t *schema.Table) []string {
+	return []string{fmt.Sprintf(`DROP TABLE %s`, d.Quote(t.Name))}
+}
+
+func (d *MySQL) RenameTable(t *schema.Table, name string) []string {
+	return []string{fmt.Sprintf(`ALTER TABLE %s RENAME TO %s`, d.Quote(t.Name), d.Quote(name))}
+}
+
+// AlterTable returns an ALTER TABLE statement that has all the changes.
+// The renamed column is changed into the expected definition at once if it
+// is modified as well.
+func (d *MySQL) AlterTable(diff *schema.TableDiff) []string {
+	var specs []string
+	for _, fk := range diff.DropForeignKeys {
+		specs = append(specs, fmt.Sprintf(`DROP FOREIGN KEY %s`, d.Quote(fk.Name)))
+	}
+	renamed := make(map[string]bool)
+	for _, r := range diff.RenameColumns {
+		renamed[r.Expected.Name] = true
+		c := r.Current
+		for _, m := range diff.ModifyColumns {
+			if m.Expected.Name == r.Expected.Name {
+				c = m.Expected
+			}
+		}
+		column := *c
+		column.Name = r.Expected.Name
+		specs = append(specs, fmt.Sprintf(`CHANGE %s %s`, d.Quote(r.Current.Name), d.columnSQL(diff.Current, &column, false, c.Unique && !r.Current.Unique)))
+		switch {
+		case r.Current.Unique && c.Unique:
+			specs = append(specs, fmt.Sprintf(`RENAME INDEX %s TO %s`, d.Quote(r.Current.Name), d.Quote(r.Expected.Name)))
+		case r.Current.Unique:
+			specs = append(specs, fmt.Sprintf(`DROP INDEX %s`, d.Quote(r.Current.Name)))
+		}
+	}
+	for _, c := range diff.AddColumns {
+		specs = append(specs, fmt.Sprintf(`ADD %s %s`, d.columnSQL(diff.Current, c, false, c.Unique), d.position(diff.Expected, c)))
+	}
+	for _, c := range diff.DropColumns {
+		specs = append(specs, fmt.Sprintf(`DROP %s`, d.Quote(c.Name)))
+	}
+	var recreated []string
+	for _, m := range diff.ModifyColumns {
+		if renamed[m.Expected.Name] {
+			continue
+		}
+		if isVirtual(m.Current) != isVirtual(m.Expected) {
+			// the virtual column can't be modified into the other kinds of
+			// the column and vice versa.
+			specs = append(specs,
+				fmt.Sprintf(`DROP %s`, d.Quote(m.Current.Name)),
+				fmt.Sprintf(`ADD %s %s`, d.columnSQL(diff.Current, m.Expected, false, m.Expected.Unique), d.position(diff.Expected, m.Expected)))
+			recreated = append(recreated, m.Expected.Name)
+			continue
+		}
+		// the UNIQUE in the column definition adds a new index every time.
+		specs = append(specs, fmt.Sprintf(`MODIFY %s`, d.columnSQL(diff.Current, m.Expected, false, m.Expected.Unique && !m.Current.Unique)))
+		if m.Current.Unique && !m.Expected.Unique {
+			specs = append(specs, fmt.Sprintf(`DROP INDEX %s`, d.Quote(m.Expected.Name)))
+		}
+	}
+	// the indexes on the recreated columns are created again as well.
+	keptIndexes := recreatedIndexes(diff, recreated)
+	for _, index := range append(keptIndexes, diff.DropIndexes...) {
+		if index.IsPrimaryKey() {
+			specs = append(specs, "DROP PRIMARY KEY")
+		} else {
+			specs = append(specs, fmt.Sprintf(`DROP INDEX %s`, d.Quote(index.Name)))
+		}
+	}
+	for _, index := range append(keptIndexes, diff.AddIndexes...) {
+		specs = append(specs, "ADD "+d.indexSQL(index))
+	}
+	for _, fk := range diff.AddForeignKeys {
+		specs = append(specs, "ADD "+foreignKeySQL(d, fk))
+	}
+	if len(specs) == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf(`ALTER TABLE %s %s`, d.Quote(diff.Expected.Name), strings.Join(specs, ", "))}
+}
+
+// isVirtual returns whether the column is the virtual generated column.
+func isVirtual(c *schema.Column) bool {
+	return c.Generated != "" && !c.Stored
+}
+
+// columnSQL returns the definition of the column in the table that has the
+// default character set.
+func (d *MySQL) columnSQL(t *schema.Table, c *schema.Column, primaryKey, unique bool) string {
+	column := []string{d.Quote(c.Name), d.columnType(t, c)}
+	if c.Charset != "" {
+		column = append(column, "CHARACTER SET", c.Charset)
+	}
+	if c.Collation != "" {
+		column = append(column, "COLLATE", c.Collation)
+	}
+	if c.Generated != "" {
+		column = append(column, generatedSQL(c))
+	}
+	if !c.Nullable {
+		column = append(column, "NOT NULL")
+	}
+	if c.Default != "" {
+		column = append(column, "DEFAULT", formatDefault(d, c))
+	}
+	if c.OnUpdate != "" {
+		column = append(column, "ON UPDATE", c.OnUpdate)
+	}
+	if primaryKey {
+		column = append(column, "PRIMARY KEY")
+	}
+	if c.AutoIncrement {
+		column = append(column, "AUTO_INCREMENT")
+	}
+	if unique {
+		column = append(column, "UNIQUE")
+	}
+	if c.Comment != "" {
+		column = append(column, "COMMENT", d.QuoteString(c.Comment))
+	}
+	return strings.Join(column, " ")
+}
+
+func (d *MySQL) indexSQL(index *schema.Index) string {
+	if index.IsPrimaryKey() {
+		return fmt.Sprintf("PRIMARY KEY (%s)", quoteNames(d, index.Columns))
+	}
+	keyType := "INDEX"
+	if index.Unique {
+		keyType = "UNIQUE"
+	}
+	return fmt.Sprintf("%s %s (%s)", keyType, d.Quote(index.Name), quoteNames(d, index.Columns))
+}
+
+// position returns the position of the column in the table for ADD COLUMN.
+func (d *MySQL) position(t *schema.Table, c *schema.Column) string {
+	for i, column := range t.Columns {
+		if column.Name == c.Name && i > 0 {
+			return fmt.Sprintf(`AFTER %s`, d.Quote(t.Columns[i-1].Name))
+		}
+	}
+	return "FIRST"
+}
+
+// Tables returns the tables in the current database from information_schema.
+func (d *MySQL) Tables(db *sql.DB) ([]*schema.Table, error) {
+	dbname, err := d.currentDBName(db)
+	if err != nil {
+		return nil, err
+	}
+	tables, err := d.tables(db, dbname)
+	if err != nil {
+		return nil, err
+	}
+	tableMap := make(map[string]*schema.Table)
+	for _, table := range tables {
+		tableMap[table.Name] = table
+	}
+	if err := d.columns(db, dbname, tableMap); err != nil {
+		return nil, err
+	}
+	if err := d.indexes(db, dbname, tableMap); err != nil {
+		return nil, err
+	}
+	if err := d.foreignKeys(db, dbname, tableMap); err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
+
+// DefaultCharset implements CharsetInspector.
+func (d *MySQL) DefaultCharset(db *sql.DB) (string, error) {
+	var charset sql.NullString
+	err := db.QueryRow(`SELECT @@character_set_database`).Scan(&charset)
+	return strings.ToLower(charset.String), err
+}
+
+func (d *MySQL) currentDBName(db *sql.DB) (string, error) {
+	var dbname sql.NullString
+	err := db.QueryRow(`SELECT DATABASE()`).Scan(&dbname)
+	return dbname.String, err
+}
+
+func (d *MySQL) tables(db *sql.DB, dbname string) ([]*schema.Table, error) {
+	query := `
+SELECT
+  t.TABLE_NAME,
+  t.ENGINE,
+  c.CHARACTER_SET_NAME,
+  t.TABLE_COLLATION,
+  t.TABLE_COMMENT
+FROM information_schema.TABLES t
+  LEFT JOIN information_schema.COLLATION_CHARACTER_SET_APPLICABILITY c ON c.COLLATION_NAME = t.TABLE_COLLATION
+WHERE t.TABLE_SCHEMA = ? AND t.TABLE_TYPE = 'BASE TABLE'
+ORDER BY t.TABLE_NAME`
+	rows, err := db.Query(query, dbname)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var tables []*schema.Table
+	for rows.Next() {
+		var (
+			table                      = &schema.Table{}
+			engine, charset, collation sql.NullString
+		)
+		if err := rows.Scan(&table.Name, &engine, &charset, &collation, &table.Option.Comment); err != nil {
+			return nil, err
+		}
+		table.Option.Engine = engine.String
+		table.Option.Charset = charset.String
+		table.Option.Collation = collation.String
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+func (d *MySQL) columns(db *sql.DB, dbname string, tableMap map[string]*schema.Table) error {
+	query := `
+SELECT
+  TABLE_NAME,
+  COLUMN_NAME,
+  COLUMN_DEFAULT,
+  IS_NULLABLE,
+  DATA_TYPE,
+  CHARACTER_MAXIMUM_LENGTH,
+  NUMERIC_PRECISION,
+  NUMERIC_SCALE,
+  DATETIME_PRECISION,
+  CHARACTER_SET_NAME,
+  COLLATION_NAME,
+  COLUMN_TYPE,
+  EXTRA,
+  GENERATION_EXPRESSION,
+  COLUMN_COMMENT
+FROM information_schema.COLUMNS
+WHERE TABLE_SCHEMA = ?
+ORDER BY TABLE_NAME, ORDINAL_POSITION`
+	rows, err := db.Query(query, dbname)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			tableName  string
+			column     = &schema.Column{}
+			def        sql.NullString
+			isNullable string
+			dataType   string
+			maxLength  sql.NullInt64
+			precision  sql.NullInt64
+			scale      sql.NullInt64
+			fsp        sql.NullInt64
+			charset    sql.NullString
+			collation  sql.NullString
+			columnType string
+			extra      string
+			generated  sql.NullString
+		)
+		if err := rows.Scan(
+			&tableName,
+			&column.Name,
+			&def,
+			&isNullable,
+			&dataType,
+			&maxLength,
+			&precision,
+			&scale,
+			&fsp,
+			&charset,
+			&collation,
+			&columnType,
+			&extra,
+			&generated,
+			&column.Comment,
+		); err != nil {
+			return err
+		}
+		d.setColumnType(column, dataType, columnType, uint64(maxLength.Int64))
+		switch {
+		case column.Type == "DECIMAL":
+			column.Precision, column.Scale = uint64(precision.Int64), uint64(scale.Int64)
+		case column.HasFractionalSeconds():
+			column.Precision = uint64(fsp.Int64)
+		}
+		column.Nullable = strings.ToUpper(isNullable) == "YES"
+		column.Default, column.DefaultExpr = d.parseDefault(column, def, extra)
+		if m := mysqlOnUpdateRegexp.FindStringSubmatch(extra); m != nil {
+			column.OnUpdate = schema.NormalizeDefaultExpr(m[1])
+		}
+		column.AutoIncrement = strings.Contains(extra, "auto_increment")
+		if m := mysqlGeneratedRegexp.FindStringSubmatch(extra); m != nil && generated.String != "" {
+			column.Generated = schema.TrimExpr(unescapeMySQLExpr(generated.String))
+			column.Stored = strings.ToUpper(m[1]) != "VIRTUAL"
+		}
+		column.Charset, column.Collation = charset.String, collation.String
+		if table, exist := tableMap[tableName]; exist {
+			d.normalizeCollation(table, column)
+			table.Columns = append(table.Columns, column)
+		}
+	}
+	return rows.Err()
+}
+
+// parseDefault returns the default value from COLUMN_DEFAULT and EXTRA of
+// information_schema.COLUMNS. MySQL 8.0 marks the expression by
+// DEFAULT_GENERATED, but MySQL 5.7 reports CURRENT_TIMESTAMP without it.
+func (d *MySQL) parseDefault(c *schema.Column, def sql.NullString, extra string) (string, bool) {
+	switch {
+	case !def.Valid:
+		return "", false
+	case strings.Contains(strings.ToUpper(extra), "DEFAULT_GENERATED"):
+		return schema.NormalizeDefaultExpr(unescapeMySQLExpr(def.String)), true
+	case c.IsTime() && schema.IsCurrentTimestamp(def.String):
+		return schema.NormalizeDefaultExpr(def.String), true
+	}
+	return def.String, false
+}
+
+// unescapeMySQLExpr returns the expression without the backslashes before
+// the quotes, the character set introducers of the strings and the
+// backquotes, such as "concat(a,'b')" for "concat(`a`,_utf8mb4\'b\')"
+// that MySQL 8.0 reports.
+func unescapeMySQLExpr(expr string) string {
+	expr = strings.Replace(expr, `\'`, `'`, -1)
+	buf := make([]byte, 0, len(expr))
+	quoted := false
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		if !quoted && c == '_' && (i == 0 || !isDDLIdentChar(expr[i-1])) {
+			j := i + 1
+			for j < len(expr) && isDDLIdentChar(expr[j]) {
+				j++
+			}
+			if j > i+1 && j < len(expr) && expr[j] == '\'' {
+				i = j - 1
+				continue
+			}
+		}
+		if !quoted && c == '`' {
+			continue
+		}
+		if c == '\'' {
+			quoted = !quoted
+		}
+		buf = append(buf, c)
+	}
+	return string(buf)
+}
+
+// indexes reads the indexes of the tables.
+// The UNIQUE index on a single column that has the same name as the column
+// is treated as the attribute of the column, because MySQL names the index
+// that is declared by UNIQUE in the column definition so.
+func (d *MySQL) indexes(db *sql.DB, dbname string, tableMap map[string]*schema.Table) error {
+	query := `
+SELECT
+  TABLE_NAME,
+  NON_UNIQUE,
+  INDEX_NAME,
+  SEQ_IN_INDEX,
+  COLUMN_NAME
+FROM information_schema.STATISTICS
+WHERE TABLE_SCHEMA = ?
+ORDER BY
+  TABLE_NAME,
+  INDEX_NAME,
+  SEQ_IN_INDEX
+`
+	rows, err := db.Query(query, dbname)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	// https://dev.mysql.com/doc/refman/5.6/en/show-index.html
+	var last *schema.Index
+	for rows.Next() {
+		var (
+			tableName  string
+			nonUnique  int64
+			indexName  string
+			seqInIndex int64
+			columnName string
+		)
+		if err := rows.Scan(&tableName, &nonUnique, &indexName, &seqInIndex, &columnName); err != nil {
+			return err
+		}
+		table, exist := tableMap[tableName]
+		if !exist {
+			continue
+		}
+		if seqInIndex == 1 {
+			last = &schema.Index{
+				Unique:  nonUnique == 0,
+				Name:    indexName,
+				Columns: []string{},
+			}
+			table.Indexes = append(table.Indexes, last)
+		}
+		last.Columns = append(last.Columns, columnName)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, table := range tableMap {
+		d.normalizeUniqueIndexes(table)
+	}
+	return nil
+}
+
+// foreignKeys reads the foreign key constraints of the tables.
+func (d *MySQL) foreignKeys(db *sql.DB, dbname string, tableMap map[string]*schema.Table) error {
+	query := `
+SELECT
+  k.TABLE_NAME,
+  k.CONSTRAINT_NAME,
+  k.ORDINAL_POSITION,
+  k.COLUMN_NAME,
+  k.REFERENCED_TABLE_NAME,
+  k.REFERENCED_COLUMN_NAME,
+  r.DELETE_RULE,
+  r.UPDATE_RULE
+FROM information_schema.KEY_COLUMN_USAGE k
+  INNER JOIN information_schema.REFERENTIAL_CONSTRAINTS r
+    ON r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA AND r.TABLE_NAME = k.TABLE_NAME AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME
+WHERE k.TABLE_SCHEMA = ?
+ORDER BY
+  k.TABLE_NAME,
+  k.CONSTRAINT_NAME,
+  k.ORDINAL_POSITION
+`
+	rows, err := db.Query(query, dbname)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	var last *schema.ForeignKey
+	for rows.Next() {
+		var (
+			tableName, constraintName, columnName string
+			referencedTable, referencedColumn     string
+			deleteRule, updateRule                string
+			ordinalPosition                       int64
+		)
+		if err := rows.Scan(&tableName, &constraintName, &ordinalPosition, &columnName, &referencedTable, &referencedColumn, &deleteRule, &updateRule); err != nil {
+			return err
+		}
+		table, exist := tableMap[tableName]
+		if !exist {
+			continue
+		}
+		if ordinalPosition == 1 {
+			last = &schema.ForeignKey{
+				Name:            constraintName,
+				ReferencedTable: referencedTable,
+				OnDelete:        deleteRule,
+				OnUpdate:        updateRule,
+			}
+			table.ForeignKeys = append(table.ForeignKeys, last)
+		}
+		last.Columns = append(last.Columns, columnName)
+		last.ReferencedColumns = append(last.ReferencedColumns, referencedColumn)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, table := range tableMap {
+		d.normalizeForeignKeyIndexes(table)
+	}
+	return nil
+}
+
+// normalizeForeignKeyIndexes removes the index that MySQL creates for the
+// foreign key implicitly. It has the same name and columns as the foreign key.
+func (d *MySQL) normalizeForeignKeyIndexes(table *schema.Table) {
+	indexes := table.Indexes[:0]
+	for _, index := range table.Indexes {
+		if fk := table.ForeignKey(index.Name); fk != nil && !index.Unique && reflect.DeepEqual(index.Columns, fk.Columns) {
+			continue
+		}
+		indexes = append(indexes, index)
+	}
+	table.Indexes = indexes
+}
+
+// setColumnType sets the type of the column from DATA_TYPE and COLUMN_TYPE
+// of information_schema.COLUMNS.
+func (d *MySQL) setColumnType(column *schema.Column, dataType, columnType string, maxLength uint64) {
+	column.Type = strings.ToUpper(dataType)
+	switch dataType {
+	case "tinyint":
+		if strings.HasPrefix(columnType, "tinyint(1)") {
+			column.Type = "BOOL"
+		}
+	case "int", "integer":
+		column.Type = "INT"
+	case "numeric":
+		column.Type = "DECIMAL"
+	case "enum", "set":
+		typ, values, ok := schema.ParseEnumType(columnType)
+		if !ok {
+			column.Type = schema.RawType(columnType)
+			return
+		}
+		column.Type, column.Values = typ, values
+		return
+	}
+	if !schema.IsNeutralType(column.Type) {
+		column.Type = schema.RawType(columnType)
+		return
+	}
+	if column.IsString() || column.IsBinary() {
+		// the size of the TEXT and BLOB types is in bytes regardless of the
+		// character set, so it's mapped back to the same type.
+		if lobSize, exist := mysqlLOBSizes[dataType]; exist {
+			maxLength = lobSize
+		}
+		column.Size = maxLength
+	}
+	column.Unsigned = strings.Contains(columnType, "unsigned")
+}
+
+// normalizeCollation clears the character set and the collation of the
+// column that are the same as the default of the table.
+func (d *MySQL) normalizeCollation(t *schema.Table, c *schema.Column) {
+	if strings.EqualFold(c.Charset, t.Option.Charset) {
+		c.Charset = ""
+	}
+	if strings.EqualFold(c.Collation, t.Option.Collation) {
+		c.Collation = ""
+	}
+}
+
+// ColumnCollation implements Collator. The character set of the collation is
+// the prefix of its name, such as utf8mb4 of utf8mb4_bin.
+func (d *MySQL) ColumnCollation(t *schema.Table, c *schema.Column) (charset, collation string) {
+	charset, collation = c.Charset, c.Collation
+	if charset == "" && collation != "" {
+		charset = strings.SplitN(collation, "_", 2)[0]
+	}
+	if charset == "" {
+		charset, collation = t.Option.Charset, t.Option.Collation
+	}
+	return strings.ToLower(charset), strings.ToLower(collation)
+}
+
+// normalizeUniqueIndexes replaces the UNIQUE index on a single column that
+// has the same name as the column with the attribute of the column, because
+// MySQL names the index that is declared by UNIQUE in the column definition
+// so.
+func (d *MySQL) normalizeUniqueIndexes(table *schema.Table) {
+	indexes := table.Indexes[:0]
+	for _, index := range table.Indexes {
+		if c := uniqueIndexColumn(table, index); c != nil && c.Name == index.Name {
+			c.Unique = true
+			continue
+		}
+		indexes = append(indexes, index)
+	}
+	table.Indexes = indexes
+}
+
+// ParseDDL returns the tables from the DDL such as the output of
+// `mysqldump --no-data'.
+func (d *MySQL) ParseDDL(src string) ([]*schema.Table, error) {
+	p := &ddlParser{
+		setType: func(column *schema.Column, typ string) error {
+			dataType, size, scale := typ, uint64(0), uint64(0)
+			if m := columnTypeRegexp.FindStringSubmatch(typ); m != nil {
+				dataType = m[1]
+				var err error
+				if m[2] != "" {
+					if size, err = strconv.ParseUint(m[2], 10, 64); err != nil {
+						return err
+					}
+				}
+				if m[3] != "" {
+					if scale, err = strconv.ParseUint(m[3], 10, 64); err != nil {
+						return err
+					}
+				}
+			} else if i := strings.IndexByte(typ, '('); i > 0 {
+				// the type that has the values such as enum('a','b').
+				dataType = strings.TrimSpace(typ[:i])
+			}
+			d.setColumnType(column, dataType, typ, size)
+			switch {
+			case column.Type == "DECIMAL":
+				if size == 0 {
+					size = 10 // default.
+				}
+				column.Precision, column.Scale = size, scale
+			case column.HasFractionalSeconds():
+				column.Precision = size
+			}
+			return nil
+		},
+	}
+	tables, err := p.parse(src)
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range tables {
+		if pk := table.PrimaryKey(); pk != nil {
+			// the columns of the primary key are implicitly NOT NULL.
+			for _, name := range pk.Columns {
+				if c := table.Column(name); c != nil {
+					c.Nullable = false
+				}
+			}
+		}
+		for _, index := range table.Indexes {
+			if index.Name == "" && len(index.Columns) > 0 {
+				// MySQL names the index by the first column.
+				index.Name = index.Columns[0]
+			}
+		}
+		for _, column := range table.Columns {
+			d.normalizeCollation(table, column)
+			if column.DefaultExpr {
+				column.Default = schema.NormalizeDefaultExpr(unescapeMySQLExpr(column.Default))
+			}
+			if column.Generated != "" {
+				column.Generated = schema.TrimExpr(unescapeMySQLExpr(column.Generated))
+			}
+		}
+		d.normalizeUniqueIndexes(table)
+		d.normalizeForeignKeyIndexes(table)
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].Name < tables[j].Name
+	})
+	return tables, nil
+}
@@ -0,0 +1,679 @@
+package dialect
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/astronoka/migu/schema"
+)
+
+var postgresCastRegexp = regexp.MustCompile(`^(.*)::[a-z ]+(\(\d+(,\d+)?\))?$`)
+
+const (
+	// postgresMaxVarcharSize is the maximum length of VARCHAR in PostgreSQL.
+	postgresMaxVarcharSize = 10485760
+
+	// postgresMaxTextSize is the maximum size of TEXT in PostgreSQL.
+	// It is used as the size of TEXT in order to restore TEXT from the size.
+	postgresMaxTextSize = 1<<30 - 1
+)
+
+// PostgreSQL is the dialect of PostgreSQL 12 or later.
+// The virtual generated column needs PostgreSQL 18 or later.
+type PostgreSQL struct {
+}
+
+func (d *PostgreSQL) ColumnType(c *schema.Column) string {
+	switch c.Type {
+	case "VARCHAR":
+		return d.varchar(c.Size)
+	case "CHAR":
+		return fmt.Sprintf("CHAR(%d)", c.Size)
+	case "DECIMAL":
+		return fmt.Sprintf("NUMERIC(%d,%d)", c.Precision, c.Scale)
+	case "TEXT", "MEDIUMTEXT", "LONGTEXT":
+		return "TEXT"
+	case "TINYINT":
+		return "SMALLINT"
+	case "SMALLINT":
+		if c.Unsigned {
+			return "INTEGER"
+		}
+		return "SMALLINT"
+	case "MEDIUMINT", "INT":
+		if c.Unsigned {
+			return "BIGINT"
+		}
+		return "INTEGER"
+	case "BIGINT":
+		return "BIGINT"
+	case "BOOL":
+		return "BOOLEAN"
+	case "FLOAT":
+		return "REAL"
+	case "DOUBLE":
+		return "DOUBLE PRECISION"
+	case "DATETIME":
+		return d.withPrecision("TIMESTAMP", "", c.Precision)
+	case "TIMESTAMP":
+		return d.withPrecision("TIMESTAMP", " WITH TIME ZONE", c.Precision)
+	case "TIME":
+		return d.withPrecision("TIME", "", c.Precision)
+	case "YEAR":
+		return "SMALLINT"
+	case "BINARY", "VARBINARY", "BLOB", "MEDIUMBLOB", "LONGBLOB":
+		return "BYTEA"
+	case "JSON":
+		return "JSONB"
+	case "ENUM", "SET":
+		// ENUM of PostgreSQL is the type that is created separately, so
+		// these are TEXT without the constraint of the values, and changing
+		// the values is nothing to migrate.
+		return "TEXT"
+	}
+	return c.Type
+}
+
+// withPrecision returns the time type with the fractional seconds precision.
+// The precision is omitted if it's zero, because the precision of the time
+// types in PostgreSQL is not limited by default.
+func (d *PostgreSQL) withPrecision(typ, suffix string, precision uint64) string {
+	if precision > 0 {
+		return fmt.Sprintf("%s(%d)%s", typ, precision, suffix)
+	}
+	return typ + suffix
+}
+
+func (d *PostgreSQL) Quote(s string) string {
+	return fmt.Sprintf(`"%s"`, strings.Replace(s, `"`, `""`, -1))
+}
+
+func (d *PostgreSQL) QuoteString(s string) string {
+	return fmt.Sprintf("'%s'", strings.Replace(s, "'", "''", -1))
+}
+
+func (d *PostgreSQL) varchar(size uint64) string {
+	if size == 0 {
+		size = 255 // default.
+	}
+	if size <= postgresMaxVarcharSize {
+		return fmt.Sprintf("VARCHAR(%d)", size)
+	}
+	return "TEXT"
+}
+
+// CreateTable returns CREATE TABLE statement followed by CREATE INDEX and
+// COMMENT ON statements, because PostgreSQL doesn't support the index and
+// the comment in CREATE TABLE.
+func (d *PostgreSQL) CreateTable(t *schema.Table) []string {
+	pk := inlinePrimaryKey(t)
+	definitions := make([]string, 0, len(t.Columns)+len(t.ForeignKeys)+1)
+	for _, c := range t.Columns {
+		definitions = append(definitions, d.columnSQL(c, c.Name == pk))
+	}
+	if index := t.PrimaryKey(); index != nil && pk == "" {
+		definitions = append(definitions, fmt.Sprintf("PRIMARY KEY (%s)", quoteNames(d, index.Columns)))
+	}
+	for _, fk := range t.ForeignKeys {
+		definitions = append(definitions, foreignKeySQL(d, fk))
+	}
+	queries := []string{fmt.Sprintf(`CREATE TABLE %s (
+  %s
+)`, d.Quote(t.Name), strings.Join(definitions, ", "))}
+	for _, index := range t.Indexes {
+		if !index.IsPrimaryKey() {
+			queries = append(queries, createIndexSQL(d, t.Name, index))
+		}
+	}
+	for _, c := range t.Columns {
+		if c.Comment != "" {
+			queries = append(queries, d.commentSQL(t.Name, c))
+		}
+	}
+	return queries
+}
+
+func (d *PostgreSQL) DropTable(t *schema.Table) []string {
+	return []string{fmt.Sprintf(`DROP TABLE %s`, d.Quote(t.Name))}
+}
+
+// RenameTable returns the statements to rename the table and its
+// constraints that are named after the table.
+func (d *PostgreSQL) RenameTable(t *schema.Table, name string) []string {
+	queries := []string{fmt.Sprintf(`ALTER TABLE %s RENAME TO %s`, d.Quote(t.Name), d.Quote(name))}
+	if t.PrimaryKey() != nil {
+		queries = append(queries, d.renameConstraintSQL(name, t.Name+"_pkey", name+"_pkey"))
+	}
+	for _, c := range t.Columns {
+		if c.Unique {
+			queries = append(queries, d.renameConstraintSQL(name, d.uniqueConstraint(t.Name, c.Name), d.uniqueConstraint(name, c.Name)))
+		}
+	}
+	return queries
+}
+
+// AlterTable returns the statements to alter the table.
+// The indexes are dropped before ALTER TABLE and created after that because
+// these aren't the part of the table in PostgreSQL.
+func (d *PostgreSQL) AlterTable(diff *schema.TableDiff) []string {
+	tableName := diff.Expected.Name
+	var (
+		before  []string
+		actions []string
+		after   []string
+	)
+	for _, fk := range diff.DropForeignKeys {
+		actions = append(actions, fmt.Sprintf(`DROP CONSTRAINT %s`, d.Quote(fk.Name)))
+	}
+	for _, index := range diff.DropIndexes {
+		if index.IsPrimaryKey() {
+			actions = append(actions, fmt.Sprintf(`DROP CONSTRAINT %s`, d.Quote(tableName+"_pkey")))
+		} else {
+			before = append(before, fmt.Sprintf(`DROP INDEX %s`, d.Quote(index.Name)))
+		}
+	}
+	// RENAME can't be combined with the other actions.
+	for _, r := range diff.RenameColumns {
+		before = append(before, fmt.Sprintf(`ALTER TABLE %s RENAME COLUMN %s TO %s`, d.Quote(tableName), d.Quote(r.Current.Name), d.Quote(r.Expected.Name)))
+		if r.Current.Unique {
+			before = append(before, d.renameConstraintSQL(tableName, d.uniqueConstraint(tableName, r.Current.Name), d.uniqueConstraint(tableName, r.Expected.Name)))
+		}
+	}
+	for _, c := range diff.DropColumns {
+		actions = append(actions, fmt.Sprintf(`DROP COLUMN %s`, d.Quote(c.Name)))
+	}
+	for _, c := range diff.AddColumns {
+		actions = append(actions, fmt.Sprintf(`ADD COLUMN %s`, d.columnSQL(c, false)))
+		if c.Comment != "" {
+			after = append(after, d.commentSQL(tableName, c))
+		}
+	}
+	var recreated []string
+	for _, m := range diff.ModifyColumns {
+		if d.recreatesColumn(m.Current, m.Expected) {
+			actions = append(actions,
+				fmt.Sprintf(`DROP COLUMN %s`, d.Quote(m.Expected.Name)),
+				fmt.Sprintf(`ADD COLUMN %s`, d.columnSQL(m.Expected, false)))
+			if m.Expected.Comment != "" {
+				after = append(after, d.commentSQL(tableName, m.Expected))
+			}
+			recreated = append(recreated, m.Expected.Name)
+			continue
+		}
+		actions = append(actions, d.modifyColumnActions(tableName, m.Current, m.Expected)...)
+		switch {
+		case m.Expected.AutoIncrement && !m.Current.AutoIncrement:
+			after = append(after, d.restartIdentitySQL(tableName, m.Expected))
+		case !m.Expected.AutoIncrement && m.Current.AutoIncrement:
+			// the SERIAL column leaves its sequence.
+			after = append(after, fmt.Sprintf(`DROP SEQUENCE IF EXISTS %s`, d.Quote(tableName+"_"+m.Expected.Name+"_seq")))
+		}
+		if m.Current.Comment != m.Expected.Comment {
+			after = append(after, d.commentSQL(tableName, m.Expected))
+		}
+	}
+	for _, index := range diff.AddIndexes {
+		if index.IsPrimaryKey() {
+			actions = append(actions, fmt.Sprintf("ADD PRIMARY KEY (%s)", quoteNames(d, index.Columns)))
+		} else {
+			after = append(after, createIndexSQL(d, tableName, index))
+		}
+	}
+	// the indexes on the recreated columns are dropped with the columns.
+	for _, index := range recreatedIndexes(diff, recreated) {
+		if !index.IsPrimaryKey() {
+			after = append(after, createIndexSQL(d, tableName, index))
+		}
+	}
+	for _, fk := range diff.AddForeignKeys {
+		actions = append(actions, "ADD "+foreignKeySQL(d, fk))
+	}
+	queries := before
+	if len(actions) > 0 {
+		queries = append(queries, fmt.Sprintf(`ALTER TABLE %s %s`, d.Quote(tableName), strings.Join(actions, ", ")))
+	}
+	return append(queries, after...)
+}
+
+// recreatesColumn reports whether the column must be dropped and added again
+// because ALTER COLUMN can't change it into the expected one. Only the stored
+// generated column can be made the normal column by DROP EXPRESSION.
+// The expression is changed by recreating the column as well, because SET
+// EXPRESSION needs PostgreSQL 17 or later.
+func (d *PostgreSQL) recreatesColumn(current, expected *schema.Column) bool {
+	if current.Generated != "" && expected.Generated != "" {
+		return !current.HasSameGenerated(expected)
+	}
+	return current.Generated != expected.Generated && !(current.Stored && expected.Generated == "")
+}
+
+func (d *PostgreSQL) modifyColumnActions(tableName string, current, expected *schema.Column) []string {
+	var actions []string
+	column := d.Quote(expected.Name)
+	if !expected.HasSameGenerated(current) {
+		// the other changes of the generated column recreate the column.
+		actions = append(actions, fmt.Sprintf(`ALTER COLUMN %s DROP EXPRESSION`, column))
+	}
+	if typ := d.ColumnType(expected); typ != d.ColumnType(current) {
+		if expected.Generated != "" {
+			// USING isn't allowed for the generated column.
+			actions = append(actions, fmt.Sprintf(`ALTER COLUMN %s TYPE %s`, column, typ))
+		} else {
+			actions = append(actions, fmt.Sprintf(`ALTER COLUMN %s TYPE %s USING %s::%s`, column, typ, column, typ))
+		}
+	}
+	if expected.Nullable != current.Nullable {
+		if expected.Nullable {
+			actions = append(actions, fmt.Sprintf(`ALTER COLUMN %s DROP NOT NULL`, column))
+		} else {
+			actions = append(actions, fmt.Sprintf(`ALTER COLUMN %s SET NOT NULL`, column))
+		}
+	}
+	switch {
+	case expected.AutoIncrement && !current.AutoIncrement:
+		actions = append(actions, fmt.Sprintf(`ALTER COLUMN %s ADD GENERATED BY DEFAULT AS IDENTITY`, column))
+	case !expected.AutoIncrement && current.AutoIncrement:
+		// the column may be SERIAL that has the default of the sequence
+		// instead of the identity column.
+		actions = append(actions,
+			fmt.Sprintf(`ALTER COLUMN %s DROP IDENTITY IF EXISTS`, column),
+			fmt.Sprintf(`ALTER COLUMN %s DROP DEFAULT`, column))
+		if expected.HasDefault() {
+			actions = append(actions, fmt.Sprintf(`ALTER COLUMN %s SET DEFAULT %s`, column, d.formatDefault(expected)))
+		}
+	case expected.AutoIncrement, expected.Generated != "":
+	case !expected.HasDefault() && current.HasDefault():
+		actions = append(actions, fmt.Sprintf(`ALTER COLUMN %s DROP DEFAULT`, column))
+	case !expected.HasSameDefault(current):
+		actions = append(actions, fmt.Sprintf(`ALTER COLUMN %s SET DEFAULT %s`, column, d.formatDefault(expected)))
+	}
+	constraint := d.Quote(d.uniqueConstraint(tableName, expected.Name))
+	switch {
+	case expected.Unique && !current.Unique:
+		actions = append(actions, fmt.Sprintf(`ADD CONSTRAINT %s UNIQUE (%s)`, constraint, column))
+	case !expected.Unique && current.Unique:
+		actions = append(actions, fmt.Sprintf(`DROP CONSTRAINT %s`, constraint))
+	}
+	return actions
+}
+
+// restartIdentitySQL returns the statement to restart the sequence of the
+// identity column after the existing values, because the sequence that is
+// added to the column starts from 1.
+func (d *PostgreSQL) restartIdentitySQL(tableName string, c *schema.Column) string {
+	return fmt.Sprintf(`SELECT setval(pg_get_serial_sequence(%s, %s), COALESCE(MAX(%s), 0) + 1, false) FROM %s`,
+		d.QuoteString(d.Quote(tableName)), d.QuoteString(c.Name), d.Quote(c.Name), d.Quote(tableName))
+}
+
+// uniqueConstraint returns the name of the UNIQUE constraint of the column
+// that is named by PostgreSQL.
+func (d *PostgreSQL) uniqueConstraint(tableName, columnName string) string {
+	return fmt.Sprintf("%s_%s_key", tableName, columnName)
+}
+
+func (d *PostgreSQL) renameConstraintSQL(tableName, name, newName string) string {
+	return fmt.Sprintf(`ALTER TABLE %s RENAME CONSTRAINT %s TO %s`, d.Quote(tableName), d.Quote(name), d.Quote(newName))
+}
+
+func (d *PostgreSQL) columnSQL(c *schema.Column, primaryKey bool) string {
+	column := []string{d.Quote(c.Name), d.ColumnType(c)}
+	if c.AutoIncrement {
+		column = append(column, "GENERATED BY DEFAULT AS IDENTITY")
+	}
+	if c.Generated != "" {
+		column = append(column, generatedSQL(c))
+	}
+	if !c.Nullable {
+		column = append(column, "NOT NULL")
+	}
+	if c.Default != "" {
+		column = append(column, "DEFAULT", d.formatDefault(c))
+	}
+	if primaryKey {
+		column = append(column, "PRIMARY KEY")
+	}
+	if c.Unique {
+		column = append(column, "UNIQUE")
+	}
+	return strings.Join(column, " ")
+}
+
+func (d *PostgreSQL) formatDefault(c *schema.Column) string {
+	if c.Type == "BOOL" && !c.DefaultExpr {
+		if c.Default == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
+	return formatDefault(d, c)
+}
+
+func (d *PostgreSQL) commentSQL(tableName string, c *schema.Column) string {
+	comment := "NULL"
+	if c.Comment != "" {
+		comment = d.QuoteString(c.Comment)
+	}
+	return fmt.Sprintf(`COMMENT ON COLUMN %s.%s IS %s`, d.Quote(tableName), d.Quote(c.Name), comment)
+}
+
+// Tables returns the tables in the current schema from the system catalogs.
+func (d *PostgreSQL) Tables(db *sql.DB) ([]*schema.Table, error) {
+	query := `
+SELECT
+  c.relname,
+  COALESCE(pg_catalog.obj_description(c.oid, 'pg_class'), '')
+FROM pg_catalog.pg_class c
+  JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = current_schema()
+  AND c.relkind = 'r'
+ORDER BY c.relname`
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var tables []*schema.Table
+	tableMap := make(map[string]*schema.Table)
+	for rows.Next() {
+		table := &schema.Table{}
+		if err := rows.Scan(&table.Name, &table.Option.Comment); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+		tableMap[table.Name] = table
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := d.columns(db, tableMap); err != nil {
+		return nil, err
+	}
+	if err := d.indexes(db, tableMap); err != nil {
+		return nil, err
+	}
+	if err := d.foreignKeys(db, tableMap); err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
+
+func (d *PostgreSQL) columns(db *sql.DB, tableMap map[string]*schema.Table) error {
+	query := `
+SELECT
+  c.relname,
+  a.attname,
+  pg_catalog.pg_get_expr(ad.adbin, ad.adrelid),
+  a.attnotnull,
+  pg_catalog.format_type(a.atttypid, NULL),
+  pg_catalog.format_type(a.atttypid, a.atttypmod),
+  CASE WHEN a.atttypid IN (1042, 1043) AND a.atttypmod > 4 THEN a.atttypmod - 4 END,
+  CASE WHEN a.atttypid = 1700 AND a.atttypmod >= 4 THEN ((a.atttypmod - 4) >> 16) & 65535 END,
+  CASE WHEN a.atttypid = 1700 AND a.atttypmod >= 4 THEN (a.atttypmod - 4) & 65535 END,
+  CASE WHEN a.atttypid IN (1083, 1114, 1184) AND a.atttypmod >= 0 THEN a.atttypmod END,
+  a.attidentity <> '',
+  a.attgenerated,
+  COALESCE(pg_catalog.col_description(c.oid, a.attnum), '')
+FROM pg_catalog.pg_attribute a
+  JOIN pg_catalog.pg_class c ON c.oid = a.attrelid
+  JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+  LEFT JOIN pg_catalog.pg_attrdef ad ON ad.adrelid = a.attrelid AND ad.adnum = a.attnum
+WHERE n.nspname = current_schema()
+  AND c.relkind = 'r'
+  AND a.attnum > 0
+  AND NOT a.attisdropped
+ORDER BY c.relname, a.attnum`
+	rows, err := db.Query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			tableName  string
+			column     = &schema.Column{}
+			def        sql.NullString
+			notNull    bool
+			dataType   string
+			fullType   string
+			maxLength  sql.NullInt64
+			precision  sql.NullInt64
+			scale      sql.NullInt64
+			fsp        sql.NullInt64
+			isIdentity bool
+			generated  string
+		)
+		if err := rows.Scan(
+			&tableName,
+			&column.Name,
+			&def,
+			&notNull,
+			&dataType,
+			&fullType,
+			&maxLength,
+			&precision,
+			&scale,
+			&fsp,
+			&isIdentity,
+			&generated,
+			&column.Comment,
+		); err != nil {
+			return err
+		}
+		column.Type, column.Size = d.schemaType(dataType, maxLength)
+		switch {
+		case !schema.IsNeutralType(column.Type):
+			column.Type = schema.RawType(fullType)
+		case column.Type == "DECIMAL":
+			column.Precision, column.Scale = uint64(precision.Int64), uint64(scale.Int64)
+		case column.HasFractionalSeconds():
+			column.Precision = uint64(fsp.Int64)
+		}
+		column.Nullable = !notNull
+		// pg_attrdef has the expression of the generated column as well.
+		if generated != "" {
+			column.Generated, column.Stored = schema.TrimExpr(def.String), generated == "s"
+		} else if isIdentity || strings.HasPrefix(def.String, "nextval(") {
+			column.AutoIncrement = true
+		} else if def.Valid {
+			column.Default, column.DefaultExpr = d.parseDefault(def.String)
+			if column.Type == "BOOL" && !column.DefaultExpr {
+				column.Default = normalizeBoolDefault(column.Default)
+			}
+		}
+		if table, exist := tableMap[tableName]; exist {
+			table.Columns = append(table.Columns, column)
+		}
+	}
+	return rows.Err()
+}
+
+// schemaType returns the type of the schema from the type in PostgreSQL.
+func (d *PostgreSQL) schemaType(dataType string, maxLength sql.NullInt64) (typ string, size uint64) {
+	switch dataType {
+	case "character varying":
+		if !maxLength.Valid {
+			return "TEXT", postgresMaxTextSize
+		}
+		return "VARCHAR", uint64(maxLength.Int64)
+	case "character":
+		return "CHAR", uint64(maxLength.Int64)
+	case "text":
+		return "TEXT", postgresMaxTextSize
+	case "integer":
+		return "INT", 0
+	case "boolean":
+		return "BOOL", 0
+	case "real":
+		return "FLOAT", 0
+	case "double precision":
+		return "DOUBLE", 0
+	case "numeric":
+		return "DECIMAL", 0
+	case "bytea":
+		return "BLOB", 0
+	case "timestamp without time zone":
+		return "DATETIME", 0
+	case "timestamp with time zone":
+		return "TIMESTAMP", 0
+	case "time without time zone":
+		return "TIME", 0
+	case "jsonb", "json":
+		return "JSON", 0
+	}
+	return strings.ToUpper(dataType), 0
+}
+
+// indexes reads the indexes of the tables.
+// The UNIQUE constraints on a single column are treated as the attribute of
+// the column because these are declared by UNIQUE in the column definition.
+func (d *PostgreSQL) indexes(db *sql.DB, tableMap map[string]*schema.Table) error {
+	query := `
+SELECT
+  t.relname,
+  i.relname,
+  ix.indisunique,
+  ix.indisprimary,
+  con.oid IS NOT NULL,
+  ix.indnatts,
+  a.attname
+FROM pg_catalog.pg_index ix
+  JOIN pg_catalog.pg_class t ON t.oid = ix.indrelid
+  JOIN pg_catalog.pg_class i ON i.oid = ix.indexrelid
+  JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace
+  LEFT JOIN pg_catalog.pg_constraint con ON con.conindid = ix.indexrelid AND con.contype = 'u'
+  CROSS JOIN LATERAL unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, seq)
+  JOIN pg_catalog.pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+WHERE n.nspname = current_schema()
+ORDER BY t.relname, i.relname, k.seq`
+	rows, err := db.Query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	var (
+		last      *schema.Index
+		lastTable string
+	)
+	for rows.Next() {
+		var (
+			tableName    string
+			indexName    string
+			unique       bool
+			primary      bool
+			isConstraint bool
+			numColumns   int64
+			columnName   string
+		)
+		if err := rows.Scan(&tableName, &indexName, &unique, &primary, &isConstraint, &numColumns, &columnName); err != nil {
+			return err
+		}
+		table, exist := tableMap[tableName]
+		if !exist {
+			continue
+		}
+		switch {
+		case primary:
+			indexName = schema.PrimaryKeyName
+		case isConstraint && numColumns == 1:
+			if c := table.Column(columnName); c != nil {
+				c.Unique = true
+			}
+			continue
+		}
+		if last == nil || lastTable != tableName || last.Name != indexName {
+			last = &schema.Index{
+				Name:    indexName,
+				Unique:  unique,
+				Columns: []string{},
+			}
+			lastTable = tableName
+			table.Indexes = append(table.Indexes, last)
+		}
+		last.Columns = append(last.Columns, columnName)
+	}
+	return rows.Err()
+}
+
+// postgresForeignKeyActions maps the referential action codes of
+// pg_constraint to the actions.
+var postgresForeignKeyActions = map[string]string{
+	"a": "NO ACTION",
+	"r": "RESTRICT",
+	"c": "CASCADE",
+	"n": "SET NULL",
+	"d": "SET DEFAULT",
+}
+
+// foreignKeys reads the foreign key constraints of the tables.
+func (d *PostgreSQL) foreignKeys(db *sql.DB, tableMap map[string]*schema.Table) error {
+	query := `
+SELECT
+  t.relname,
+  con.conname,
+  k.seq,
+  a.attname,
+  rt.relname,
+  ra.attname,
+  con.confdeltype,
+  con.confupdtype
+FROM pg_catalog.pg_constraint con
+  JOIN pg_catalog.pg_class t ON t.oid = con.conrelid
+  JOIN pg_catalog.pg_class rt ON rt.oid = con.confrelid
+  JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace
+  CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, refattnum, seq)
+  JOIN pg_catalog.pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+  JOIN pg_catalog.pg_attribute ra ON ra.attrelid = con.confrelid AND ra.attnum = k.refattnum
+WHERE n.nspname = current_schema()
+  AND con.contype = 'f'
+ORDER BY t.relname, con.conname, k.seq`
+	rows, err := db.Query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	var last *schema.ForeignKey
+	for rows.Next() {
+		var (
+			tableName, constraintName  string
+			seq                        int64
+			columnName                 string
+			referencedTable            string
+			referencedColumn           string
+			deleteAction, updateAction string
+		)
+		if err := rows.Scan(&tableName, &constraintName, &seq, &columnName, &referencedTable, &referencedColumn, &deleteAction, &updateAction); err != nil {
+			return err
+		}
+		table, exist := tableMap[tableName]
+		if !exist {
+			continue
+		}
+		if seq == 1 {
+			last = &schema.ForeignKey{
+				Name:            constraintName,
+				ReferencedTable: referencedTable,
+				OnDelete:        postgresForeignKeyActions[deleteAction],
+				OnUpdate:        postgresForeignKeyActions[updateAction],
+			}
+			table.ForeignKeys = append(table.ForeignKeys, last)
+		}
+		last.Columns = append(last.Columns, columnName)
+		last.ReferencedColumns = append(last.ReferencedColumns, referencedColumn)
+	}
+	return rows.Err()
+}
+
+// parseDefault strips the type casts from the default value expression and
+// returns the value in order to compare with the `default' tag of the field.
+func (d *PostgreSQL) parseDefault(def string) (string, bool) {
+	for {
+		m := postgresCastRegexp.FindStringSubmatch(def)
+		if m == nil {
+			break
+		}
+		def = m[1]
+	}
+	return parseDefault(def)
+}
@@ -0,0 +1,249 @@
+package dialect
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/astronoka/migu/schema"
+)
+
+// fakeDriver returns the rows of the registered results in order of the
+// queries, in order to read the catalogs without the database.
+type fakeDriver struct {
+	mu      sync.Mutex
+	results [][][]driver.Value
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{d: d}, nil
+}
+
+type fakeConn struct {
+	d *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{d: c.d}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("transaction is not supported")
+}
+
+type fakeStmt struct {
+	d *fakeDriver
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, fmt.Errorf("exec is not supported")
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	if len(s.d.results) == 0 {
+		return nil, fmt.Errorf("unexpected query")
+	}
+	rows := &fakeRows{values: s.d.results[0]}
+	s.d.results = s.d.results[1:]
+	return rows, nil
+}
+
+type fakeRows struct {
+	values [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	if len(r.values) == 0 {
+		return nil
+	}
+	return make([]string, len(r.values[0]))
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+var fakeDrivers = map[string]*fakeDriver{}
+
+func openFake(t *testing.T, results ...[][]driver.Value) *sql.DB {
+	name := "fake_" + t.Name()
+	d, exist := fakeDrivers[name]
+	if !exist {
+		d = &fakeDriver{}
+		fakeDrivers[name] = d
+		sql.Register(name, d)
+	}
+	d.results = results
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestPostgreSQLTables(t *testing.T) {
+	db := openFake(t,
+		// tables.
+		[][]driver.Value{
+			{"post", ""},
+			{"user", "users"},
+		},
+		// columns.
+		[][]driver.Value{
+			{"post", "id", "nextval('post_id_seq'::regclass)", true, "integer", "integer", nil, nil, nil, nil, false, "", ""},
+			{"post", "user_id", nil, false, "bigint", "bigint", nil, nil, nil, nil, false, "", ""},
+			{"user", "id", nil, true, "bigint", "bigint", nil, nil, nil, nil, true, "", ""},
+			{"user", "name", "'alice'::character varying", true, "character varying", "character varying(100)", int64(100), nil, nil, nil, false, "", "the name"},
+			{"user", "bio", nil, true, "text", "text", nil, nil, nil, nil, false, "", ""},
+			{"user", "score", "0.5", true, "numeric", "numeric(4,2)", nil, int64(4), int64(2), nil, false, "", ""},
+			{"user", "active", "true", true, "boolean", "boolean", nil, nil, nil, nil, false, "", ""},
+			{"user", "created_at", "CURRENT_TIMESTAMP", true, "timestamp without time zone", "timestamp(3) without time zone", nil, nil, nil, int64(3), false, "", ""},
+			{"user", "lower_name", "lower((name)::text)", false, "text", "text", nil, nil, nil, nil, false, "s", ""},
+			{"user", "point", nil, false, "point", "point", nil, nil, nil, nil, false, "", ""},
+		},
+		// indexes.
+		[][]driver.Value{
+			{"post", "post_pkey", true, true, false, int64(1), "id"},
+			{"post", "post_user_id_index", false, false, false, int64(1), "user_id"},
+			{"user", "user_name_key", true, false, true, int64(1), "name"},
+			{"user", "user_pkey", true, true, false, int64(1), "id"},
+		},
+		// foreign keys.
+		[][]driver.Value{
+			{"post", "fk_post_user_id", int64(1), "user_id", "user", "id", "c", "a"},
+		},
+	)
+	defer db.Close()
+	d := &PostgreSQL{}
+	actual, err := d.Tables(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk := &schema.Index{Name: schema.PrimaryKeyName, Unique: true, Columns: []string{"id"}}
+	expect := []*schema.Table{
+		{
+			Name: "post",
+			Columns: []*schema.Column{
+				{Name: "id", Type: "INT", AutoIncrement: true},
+				{Name: "user_id", Type: "BIGINT", Nullable: true},
+			},
+			Indexes: []*schema.Index{
+				pk,
+				{Name: "post_user_id_index", Columns: []string{"user_id"}},
+			},
+			ForeignKeys: []*schema.ForeignKey{
+				{
+					Name:              "fk_post_user_id",
+					Columns:           []string{"user_id"},
+					ReferencedTable:   "user",
+					ReferencedColumns: []string{"id"},
+					OnDelete:          "CASCADE",
+					OnUpdate:          "NO ACTION",
+				},
+			},
+		},
+		{
+			Name: "user",
+			Columns: []*schema.Column{
+				{Name: "id", Type: "BIGINT", AutoIncrement: true},
+				{Name: "name", Type: "VARCHAR", Size: 100, Default: "alice", Unique: true, Comment: "the name"},
+				{Name: "bio", Type: "TEXT", Size: postgresMaxTextSize},
+				{Name: "score", Type: "DECIMAL", Precision: 4, Scale: 2, Default: "0.5"},
+				{Name: "active", Type: "BOOL", Default: "1"},
+				{Name: "created_at", Type: "DATETIME", Precision: 3, Default: "CURRENT_TIMESTAMP", DefaultExpr: true},
+				{Name: "lower_name", Type: "TEXT", Size: postgresMaxTextSize, Nullable: true, Generated: "lower((name)::text)", Stored: true},
+				{Name: "point", Type: schema.RawType("point"), Nullable: true},
+			},
+			Indexes: []*schema.Index{pk},
+			Option:  schema.TableOption{Comment: "users"},
+		},
+	}
+	if !reflect.DeepEqual(actual, expect) {
+		for i := range actual {
+			t.Errorf("Tables(db)[%d] => %#v", i, actual[i])
+			for _, c := range actual[i].Columns {
+				t.Errorf("  %#v", c)
+			}
+		}
+		t.Fatalf("want %#v", expect)
+	}
+}
+
+func TestPostgreSQLAlterTableAutoIncrement(t *testing.T) {
+	d := &PostgreSQL{}
+	column := func(autoIncrement bool, def string) *schema.Column {
+		return &schema.Column{Name: "id", Type: "BIGINT", AutoIncrement: autoIncrement, Default: def}
+	}
+	table := func(c *schema.Column) *schema.Table {
+		return &schema.Table{Name: "user", Columns: []*schema.Column{c}}
+	}
+	for i, v := range []struct {
+		current, expected *schema.Column
+		expect            []string
+	}{
+		{
+			current:  column(false, ""),
+			expected: column(true, ""),
+			expect: []string{
+				`ALTER TABLE "user" ALTER COLUMN "id" ADD GENERATED BY DEFAULT AS IDENTITY`,
+				`SELECT setval(pg_get_serial_sequence('"user"', 'id'), COALESCE(MAX("id"), 0) + 1, false) FROM "user"`,
+			},
+		},
+		{
+			current:  column(true, ""),
+			expected: column(false, ""),
+			expect: []string{
+				`ALTER TABLE "user" ALTER COLUMN "id" DROP IDENTITY IF EXISTS, ALTER COLUMN "id" DROP DEFAULT`,
+				`DROP SEQUENCE IF EXISTS "user_id_seq"`,
+			},
+		},
+		{
+			current:  column(true, ""),
+			expected: column(false, "0"),
+			expect: []string{
+				`ALTER TABLE "user" ALTER COLUMN "id" DROP IDENTITY IF EXISTS, ALTER COLUMN "id" DROP DEFAULT, ALTER COLUMN "id" SET DEFAULT 0`,
+				`DROP SEQUENCE IF EXISTS "user_id_seq"`,
+			},
+		},
+	} {
+		diff := &schema.TableDiff{
+			Current:       table(v.current),
+			Expected:      table(v.expected),
+			ModifyColumns: []*schema.ColumnDiff{{Current: v.current, Expected: v.expected}},
+		}
+		if actual := d.AlterTable(diff); !reflect.DeepEqual(actual, v.expect) {
+			t.Errorf("%d: AlterTable(%#v) => %#v; want %#v", i, diff, actual, v.expect)
+		}
+	}
+	c := column(true, "")
+	if actual, expect := d.CreateTable(table(c)), []string{"CREATE TABLE \"user\" (\n  \"id\" BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL\n)"}; !reflect.DeepEqual(actual, expect) {
+		t.Errorf("CreateTable => %#v; want %#v", actual, expect)
+	}
+}
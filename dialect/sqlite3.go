@@ -0,0 +1,680 @@
+package dialect
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/astronoka/migu/schema"
+)
+
+var sqlite3AutoIncrementRegexp = regexp.MustCompile(`(?i)\bAUTOINCREMENT\b`)
+
+type SQLite3 struct {
+}
+
+func (d *SQLite3) ColumnType(c *schema.Column) string {
+	if c.AutoIncrement {
+		// AUTOINCREMENT is only allowed on an INTEGER PRIMARY KEY.
+		return "INTEGER"
+	}
+	switch c.Type {
+	case "VARCHAR":
+		return d.varchar(c.Size)
+	case "CHAR":
+		return fmt.Sprintf("CHAR(%d)", c.Size)
+	case "VARBINARY":
+		if c.Size == 0 {
+			return "BLOB"
+		}
+		return fmt.Sprintf("VARBINARY(%d)", c.Size)
+	case "BINARY":
+		return fmt.Sprintf("BINARY(%d)", c.Size)
+	case "DECIMAL":
+		return fmt.Sprintf("DECIMAL(%d,%d)", c.Precision, c.Scale)
+	case "ENUM", "SET":
+		// these are TEXT without the constraint of the values, and changing
+		// the values is nothing to migrate.
+		return "TEXT"
+	case "DATETIME", "TIMESTAMP", "TIME":
+		if c.Precision > 0 {
+			return fmt.Sprintf("%s(%d)", c.Type, c.Precision)
+		}
+	case "BOOL":
+		return "BOOLEAN"
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "BIGINT", "FLOAT", "DOUBLE":
+		if c.Unsigned {
+			return c.Type + " UNSIGNED"
+		}
+	}
+	return c.Type
+}
+
+func (d *SQLite3) Quote(s string) string {
+	return fmt.Sprintf(`"%s"`, strings.Replace(s, `"`, `""`, -1))
+}
+
+func (d *SQLite3) QuoteString(s string) string {
+	return fmt.Sprintf("'%s'", strings.Replace(s, "'", "''", -1))
+}
+
+// varchar returns VARCHAR with the length regardless of the size because
+// SQLite doesn't limit the length of the text. The length is kept in order to
+// restore the size from the schema.
+func (d *SQLite3) varchar(size uint64) string {
+	if size == 0 {
+		size = 255 // default.
+	}
+	return fmt.Sprintf("VARCHAR(%d)", size)
+}
+
+func (d *SQLite3) CreateTable(t *schema.Table) []string {
+	queries := []string{d.createTableSQL(t.Name, t)}
+	for _, index := range t.Indexes {
+		if !index.IsPrimaryKey() {
+			queries = append(queries, createIndexSQL(d, t.Name, index))
+		}
+	}
+	return queries
+}
+
+func (d *SQLite3) DropTable(t *schema.Table) []string {
+	return []string{fmt.Sprintf(`DROP TABLE %s`, d.Quote(t.Name))}
+}
+
+func (d *SQLite3) RenameTable(t *schema.Table, name string) []string {
+	return []string{fmt.Sprintf(`ALTER TABLE %s RENAME TO %s`, d.Quote(t.Name), d.Quote(name))}
+}
+
+// AlterTable returns the statements to alter the table.
+// SQLite3 supports only a few kinds of ALTER TABLE, so the table will be
+// rebuilt if the changes can't be done in place.
+// See https://www.sqlite.org/lang_altertable.html#otheralter
+func (d *SQLite3) AlterTable(diff *schema.TableDiff) []string {
+	tableName := diff.Expected.Name
+	var queries []string
+	for _, r := range diff.RenameColumns {
+		queries = append(queries, fmt.Sprintf(`ALTER TABLE %s RENAME COLUMN %s TO %s`, d.Quote(tableName), d.Quote(r.Current.Name), d.Quote(r.Expected.Name)))
+	}
+	if d.NeedsRebuild(diff) {
+		return append(queries, d.rebuildTable(diff)...)
+	}
+	for _, index := range diff.DropIndexes {
+		if !index.IsPrimaryKey() {
+			queries = append(queries, fmt.Sprintf(`DROP INDEX %s`, d.Quote(index.Name)))
+		}
+	}
+	for _, c := range diff.AddColumns {
+		queries = append(queries, fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s`, d.Quote(tableName), d.columnSQL(c, false)))
+	}
+	for _, index := range diff.AddIndexes {
+		if !index.IsPrimaryKey() {
+			queries = append(queries, createIndexSQL(d, tableName, index))
+		}
+	}
+	return queries
+}
+
+// NeedsRebuild reports whether the changes can't be done by ALTER TABLE.
+// The comments and ON UPDATE of the columns are ignored because SQLite3
+// doesn't have these.
+func (d *SQLite3) NeedsRebuild(diff *schema.TableDiff) bool {
+	if len(diff.DropColumns) > 0 || len(diff.AddForeignKeys) > 0 || len(diff.DropForeignKeys) > 0 {
+		return true
+	}
+	renames := renamedColumns(diff)
+	currentPK := append([]string(nil), d.primaryKey(diff.Current)...)
+	for i, name := range currentPK {
+		if newName, ok := renames[name]; ok {
+			currentPK[i] = newName
+		}
+	}
+	if !reflect.DeepEqual(currentPK, d.primaryKey(diff.Expected)) {
+		return true
+	}
+	pk := d.primaryKey(diff.Expected)
+	for _, c := range diff.AddColumns {
+		// ADD COLUMN can't have the default of the expression and the stored
+		// generated column.
+		if c.Unique || c.AutoIncrement || (!c.Nullable && !c.HasDefault()) || (c.HasDefault() && c.DefaultExpr) || c.Stored {
+			return true
+		}
+		for _, name := range pk {
+			if name == c.Name {
+				return true
+			}
+		}
+	}
+	for _, m := range diff.ModifyColumns {
+		if d.ColumnType(m.Current) != d.ColumnType(m.Expected) ||
+			m.Current.Nullable != m.Expected.Nullable ||
+			m.Current.HasDefault() != m.Expected.HasDefault() ||
+			(m.Expected.HasDefault() && d.formatDefault(m.Current) != d.formatDefault(m.Expected)) ||
+			m.Current.AutoIncrement != m.Expected.AutoIncrement ||
+			m.Current.Unique != m.Expected.Unique ||
+			!m.Current.HasSameGenerated(m.Expected) {
+			return true
+		}
+	}
+	return false
+}
+
+// Migrate executes the queries with the foreign key constraints disabled,
+// because dropping the table to rebuild it deletes the rows of the other
+// tables that refer to it by ON DELETE CASCADE. The constraints are checked
+// by PRAGMA foreign_key_check before the commit instead.
+// See https://www.sqlite.org/lang_altertable.html#otheralter
+func (d *SQLite3) Migrate(db *sql.DB, queries []string, exec func(tx *sql.Tx, query string) error) (err error) {
+	ctx := context.Background()
+	// PRAGMA foreign_keys is the setting of the connection, and it can't be
+	// changed in the transaction.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	var enabled bool
+	if err := conn.QueryRowContext(ctx, `PRAGMA foreign_keys`).Scan(&enabled); err != nil {
+		return err
+	}
+	if enabled {
+		if _, err := conn.ExecContext(ctx, `PRAGMA foreign_keys = OFF`); err != nil {
+			return err
+		}
+		defer func() {
+			if _, e := conn.ExecContext(ctx, `PRAGMA foreign_keys = ON`); err == nil {
+				err = e
+			}
+		}()
+	}
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, query := range queries {
+		if err := exec(tx, query); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if enabled {
+		if err := d.checkForeignKeys(tx); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// checkForeignKeys returns an error if any row violates the foreign key
+// constraints.
+func (d *SQLite3) checkForeignKeys(tx *sql.Tx) error {
+	rows, err := tx.Query(`PRAGMA foreign_key_check`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		var (
+			table  string
+			rowid  sql.NullInt64
+			parent string
+			fkid   int64
+		)
+		if err := rows.Scan(&table, &rowid, &parent, &fkid); err != nil {
+			return err
+		}
+		return fmt.Errorf("the row %d of %s violates the foreign key constraint that refers to %s", rowid.Int64, table, parent)
+	}
+	return rows.Err()
+}
+
+// primaryKey returns the columns of the primary key.
+// The column of autoincrement will be the primary key because SQLite3 allows
+// AUTOINCREMENT only on an INTEGER PRIMARY KEY.
+func (d *SQLite3) primaryKey(t *schema.Table) []string {
+	for _, c := range t.Columns {
+		if c.AutoIncrement {
+			return []string{c.Name}
+		}
+	}
+	if pk := t.PrimaryKey(); pk != nil {
+		return pk.Columns
+	}
+	return nil
+}
+
+// rebuildTable returns the queries to rebuild the table by creating a new
+// table, copying the data, dropping the old table and renaming the new table.
+// The generated columns aren't copied because these can't be written.
+func (d *SQLite3) rebuildTable(diff *schema.TableDiff) []string {
+	current, expected := diff.Current, diff.Expected
+	newTableName := "new_" + expected.Name
+	var copyColumns []string
+	renamed := make(map[string]bool)
+	for _, newName := range renamedColumns(diff) {
+		renamed[newName] = true
+	}
+	var selectColumns []string
+	pk := d.primaryKey(expected)
+	for _, c := range expected.Columns {
+		switch {
+		case c.Generated != "":
+		case current.Column(c.Name) != nil || renamed[c.Name]:
+			// the renamed columns have been renamed before the rebuild.
+			copyColumns = append(copyColumns, d.Quote(c.Name))
+			selectColumns = append(selectColumns, d.Quote(c.Name))
+		case !c.Nullable && !c.HasDefault() && !c.AutoIncrement && !(len(pk) == 1 && pk[0] == c.Name):
+			// the new NOT NULL column without the default is filled with
+			// the zero value as well as MySQL adds it, because the rows
+			// can't have NULL. The primary key can't be filled with the
+			// same value.
+			copyColumns = append(copyColumns, d.Quote(c.Name))
+			selectColumns = append(selectColumns, d.zeroValue(c))
+		}
+	}
+	queries := []string{d.createTableSQL(newTableName, expected)}
+	if len(copyColumns) > 0 {
+		queries = append(queries, fmt.Sprintf(`INSERT INTO %s (%s) SELECT %s FROM %s`,
+			d.Quote(newTableName), strings.Join(copyColumns, ", "), strings.Join(selectColumns, ", "), d.Quote(current.Name)))
+	}
+	queries = append(queries,
+		fmt.Sprintf(`DROP TABLE %s`, d.Quote(current.Name)),
+		fmt.Sprintf(`ALTER TABLE %s RENAME TO %s`, d.Quote(newTableName), d.Quote(expected.Name)))
+	for _, index := range expected.Indexes {
+		if !index.IsPrimaryKey() {
+			queries = append(queries, createIndexSQL(d, expected.Name, index))
+		}
+	}
+	return queries
+}
+
+// zeroValue returns the zero value of the type of the column as SQL literal.
+// The time types are the zero value of time.Time, and ENUM is its first
+// value as well as MySQL.
+func (d *SQLite3) zeroValue(c *schema.Column) string {
+	switch {
+	case c.Type == "ENUM" && len(c.Values) > 0:
+		return d.QuoteString(c.Values[0])
+	case c.Type == "DATE":
+		return "'0001-01-01'"
+	case c.Type == "DATETIME", c.Type == "TIMESTAMP":
+		return "'0001-01-01 00:00:00'"
+	case c.Type == "TIME":
+		return "'00:00:00'"
+	case c.Type == "JSON":
+		return "'null'"
+	case c.IsString(), c.IsEnum(), !schema.IsNeutralType(c.Type):
+		return "''"
+	case c.IsBinary():
+		return "X''"
+	}
+	return "0"
+}
+
+func (d *SQLite3) createTableSQL(tableName string, t *schema.Table) string {
+	pk := d.primaryKey(t)
+	definitions := make([]string, 0, len(t.Columns)+len(t.ForeignKeys)+1)
+	for _, c := range t.Columns {
+		definitions = append(definitions, d.columnSQL(c, len(pk) == 1 && pk[0] == c.Name))
+	}
+	if len(pk) > 1 {
+		definitions = append(definitions, fmt.Sprintf("PRIMARY KEY (%s)", quoteNames(d, pk)))
+	}
+	for _, fk := range t.ForeignKeys {
+		definitions = append(definitions, foreignKeySQL(d, fk))
+	}
+	return fmt.Sprintf(`CREATE TABLE %s (
+  %s
+)`, d.Quote(tableName), strings.Join(definitions, ", "))
+}
+
+func (d *SQLite3) columnSQL(c *schema.Column, primaryKey bool) string {
+	column := []string{d.Quote(c.Name), d.ColumnType(c)}
+	if c.Generated != "" {
+		column = append(column, generatedSQL(c))
+	}
+	if !c.Nullable {
+		column = append(column, "NOT NULL")
+	}
+	if c.Default != "" {
+		column = append(column, "DEFAULT", d.formatDefault(c))
+	}
+	if primaryKey {
+		column = append(column, "PRIMARY KEY")
+	}
+	if c.AutoIncrement {
+		column = append(column, "AUTOINCREMENT")
+	}
+	if c.Unique {
+		column = append(column, "UNIQUE")
+	}
+	return strings.Join(column, " ")
+}
+
+// formatDefault returns the default value as SQL literal. SQLite3 doesn't
+// have the fractional seconds of CURRENT_TIMESTAMP.
+func (d *SQLite3) formatDefault(c *schema.Column) string {
+	if c.DefaultExpr && schema.IsCurrentTimestamp(c.Default) {
+		return "CURRENT_TIMESTAMP"
+	}
+	return formatDefault(d, c)
+}
+
+// Tables returns the tables in the main database from sqlite_master and
+// PRAGMA statements.
+func (d *SQLite3) Tables(db *sql.DB) ([]*schema.Table, error) {
+	rows, err := db.Query(`SELECT name, sql FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		tables    []*schema.Table
+		tableSQLs []string
+	)
+	for rows.Next() {
+		var (
+			table    = &schema.Table{}
+			tableSQL string
+		)
+		if err := rows.Scan(&table.Name, &tableSQL); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		tables = append(tables, table)
+		tableSQLs = append(tableSQLs, tableSQL)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i, table := range tables {
+		if err := d.columns(db, table, tableSQLs[i]); err != nil {
+			return nil, err
+		}
+		if err := d.indexes(db, table); err != nil {
+			return nil, err
+		}
+		if err := d.foreignKeys(db, table); err != nil {
+			return nil, err
+		}
+	}
+	return tables, nil
+}
+
+// columns reads the columns and the primary key of the table.
+// The expressions of the generated columns are read from the table SQL
+// because PRAGMA statements don't have these.
+func (d *SQLite3) columns(db *sql.DB, table *schema.Table, tableSQL string) error {
+	rows, err := db.Query(fmt.Sprintf(`PRAGMA table_xinfo(%s)`, d.Quote(table.Name)))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	pkColumns := map[int64]string{}
+	var rowid *schema.Column
+	for rows.Next() {
+		var (
+			cid        int64
+			column     = &schema.Column{}
+			columnType string
+			notNull    bool
+			def        sql.NullString
+			pk         int64
+			hidden     int64
+		)
+		if err := rows.Scan(&cid, &column.Name, &columnType, &notNull, &def, &pk, &hidden); err != nil {
+			return err
+		}
+		if err := d.parseType(column, strings.ToLower(columnType)); err != nil {
+			return err
+		}
+		column.Nullable = !notNull
+		if def.Valid {
+			column.Default, column.DefaultExpr = parseDefault(def.String)
+			if column.Type == "BOOL" && !column.DefaultExpr {
+				column.Default = normalizeBoolDefault(column.Default)
+			}
+		}
+		// 2 is the virtual generated column and 3 is the stored one.
+		if hidden == 2 || hidden == 3 {
+			expr, err := d.generatedExpr(tableSQL, column.Name)
+			if err != nil {
+				return err
+			}
+			column.Generated, column.Stored = expr, hidden == 3
+		}
+		if pk > 0 {
+			pkColumns[pk] = column.Name
+			if strings.ToLower(columnType) == "integer" {
+				rowid = column
+				if sqlite3AutoIncrementRegexp.MatchString(tableSQL) {
+					column.AutoIncrement = true
+				}
+			}
+		}
+		table.Columns = append(table.Columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(pkColumns) == 0 {
+		return nil
+	}
+	// INTEGER PRIMARY KEY is the alias of the rowid, that can't be NULL
+	// even if NOT NULL isn't declared.
+	if rowid != nil && len(pkColumns) == 1 {
+		rowid.Nullable = false
+	}
+	pk := &schema.Index{
+		Name:    schema.PrimaryKeyName,
+		Unique:  true,
+		Columns: []string{},
+	}
+	// the position in the primary key starts from 1.
+	for i := int64(1); i <= int64(len(pkColumns)); i++ {
+		pk.Columns = append(pk.Columns, pkColumns[i])
+	}
+	table.Indexes = append(table.Indexes, pk)
+	return nil
+}
+
+// generatedExpr returns the expression of the generated column in the table
+// SQL.
+func (d *SQLite3) generatedExpr(tableSQL, name string) (string, error) {
+	p := &ddlParser{
+		setType: func(column *schema.Column, typ string) error {
+			return nil
+		},
+	}
+	tables, err := p.parse(tableSQL)
+	if err != nil {
+		return "", err
+	}
+	for _, table := range tables {
+		if c := table.Column(name); c != nil {
+			return c.Generated, nil
+		}
+	}
+	return "", fmt.Errorf("generated column %s is not found", name)
+}
+
+// parseType sets the type of the column from the declared type.
+// SQLite3 keeps the declared type as is, so the type can be restored.
+func (d *SQLite3) parseType(column *schema.Column, columnType string) error {
+	m := columnTypeRegexp.FindStringSubmatch(columnType)
+	if m == nil {
+		column.Type = schema.RawType(columnType)
+		return nil
+	}
+	switch m[1] {
+	case "integer":
+		column.Type = "INT"
+	case "boolean":
+		column.Type = "BOOL"
+	case "numeric":
+		column.Type = "DECIMAL"
+	case "real":
+		// REAL is the 8-byte floating point number.
+		column.Type = "DOUBLE"
+	default:
+		column.Type = strings.ToUpper(m[1])
+	}
+	if !schema.IsNeutralType(column.Type) {
+		column.Type = schema.RawType(columnType)
+		return nil
+	}
+	if m[2] != "" && (column.IsString() || column.IsBinary()) {
+		size, err := strconv.ParseUint(m[2], 10, 64)
+		if err != nil {
+			return err
+		}
+		column.Size = size
+	}
+	if column.Type == "DECIMAL" || column.HasFractionalSeconds() {
+		// SQLite3 keeps the declared precision and scale as is.
+		var err error
+		if m[2] != "" {
+			if column.Precision, err = strconv.ParseUint(m[2], 10, 64); err != nil {
+				return err
+			}
+		}
+		if m[3] != "" {
+			if column.Scale, err = strconv.ParseUint(m[3], 10, 64); err != nil {
+				return err
+			}
+		}
+	}
+	column.Unsigned = m[4] != ""
+	return nil
+}
+
+// indexes reads the indexes of the table except the primary key.
+// The UNIQUE constraints on a single column are treated as the attribute of
+// the column because these are declared by UNIQUE in the column definition.
+func (d *SQLite3) indexes(db *sql.DB, table *schema.Table) error {
+	rows, err := db.Query(fmt.Sprintf(`PRAGMA index_list(%s)`, d.Quote(table.Name)))
+	if err != nil {
+		return err
+	}
+	type indexList struct {
+		name   string
+		unique bool
+		origin string
+	}
+	var lists []indexList
+	for rows.Next() {
+		var (
+			seq     int64
+			l       indexList
+			partial bool
+		)
+		if err := rows.Scan(&seq, &l.name, &l.unique, &l.origin, &partial); err != nil {
+			rows.Close()
+			return err
+		}
+		lists = append(lists, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	sort.Slice(lists, func(i, j int) bool {
+		return lists[i].name < lists[j].name
+	})
+	for _, l := range lists {
+		if l.origin == "pk" {
+			continue
+		}
+		columnNames, err := d.indexColumns(db, l.name)
+		if err != nil {
+			return err
+		}
+		if l.origin == "u" && len(columnNames) == 1 {
+			if c := table.Column(columnNames[0]); c != nil {
+				c.Unique = true
+			}
+			continue
+		}
+		table.Indexes = append(table.Indexes, &schema.Index{
+			Name:    l.name,
+			Unique:  l.unique,
+			Columns: columnNames,
+		})
+	}
+	return nil
+}
+
+func (d *SQLite3) indexColumns(db *sql.DB, indexName string) ([]string, error) {
+	rows, err := db.Query(fmt.Sprintf(`PRAGMA index_info(%s)`, d.Quote(indexName)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columnNames := []string{}
+	for rows.Next() {
+		var (
+			seqno int64
+			cid   int64
+			name  string
+		)
+		if err := rows.Scan(&seqno, &cid, &name); err != nil {
+			return nil, err
+		}
+		columnNames = append(columnNames, name)
+	}
+	return columnNames, rows.Err()
+}
+
+// foreignKeys reads the foreign key constraints of the table.
+// SQLite3 doesn't keep the names of the constraints, so these are unnamed.
+func (d *SQLite3) foreignKeys(db *sql.DB, table *schema.Table) error {
+	rows, err := db.Query(fmt.Sprintf(`PRAGMA foreign_key_list(%s)`, d.Quote(table.Name)))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	fks := map[int64]*schema.ForeignKey{}
+	var ids []int64
+	for rows.Next() {
+		var (
+			id, seq            int64
+			referencedTable    string
+			from               string
+			to                 sql.NullString
+			onUpdate, onDelete string
+			match              string
+		)
+		if err := rows.Scan(&id, &seq, &referencedTable, &from, &to, &onUpdate, &onDelete, &match); err != nil {
+			return err
+		}
+		fk, exist := fks[id]
+		if !exist {
+			fk = &schema.ForeignKey{
+				ReferencedTable: referencedTable,
+				OnDelete:        onDelete,
+				OnUpdate:        onUpdate,
+			}
+			fks[id] = fk
+			ids = append(ids, id)
+		}
+		fk.Columns = append(fk.Columns, from)
+		fk.ReferencedColumns = append(fk.ReferencedColumns, to.String)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	// the constraints are listed in the reverse order of the declaration.
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] > ids[j]
+	})
+	for _, id := range ids {
+		table.ForeignKeys = append(table.ForeignKeys, fks[id])
+	}
+	return nil
+}
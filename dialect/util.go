@@ -0,0 +1,157 @@
+package dialect
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/astronoka/migu/schema"
+)
+
+var (
+	quotedStringRegexp = regexp.MustCompile(`^'((?:[^']|'')*)'$`)
+
+	// columnTypeRegexp matches the declared type of the column such as
+	// "varchar(255)", "decimal(20,8)" and "int(10) unsigned".
+	columnTypeRegexp = regexp.MustCompile(`^([a-z ]+?)(?:\((\d+)(?:,\s*(\d+))?\))?( unsigned)?$`)
+
+	numberRegexp = regexp.MustCompile(`^[-+]?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?$`)
+)
+
+// normalizeBoolDefault returns the default value of the boolean column as
+// "1" or "0".
+func normalizeBoolDefault(s string) string {
+	switch strings.ToLower(s) {
+	case "1", "true", "on":
+		return "1"
+	}
+	return "0"
+}
+
+// formatDefault returns the default value as SQL literal. The expression
+// other than NULL and CURRENT_TIMESTAMP is enclosed in parentheses.
+func formatDefault(d Dialect, c *schema.Column) string {
+	switch {
+	case c.DefaultExpr:
+		if c.Default == "NULL" || schema.IsCurrentTimestamp(c.Default) {
+			return c.Default
+		}
+		return "(" + c.Default + ")"
+	case c.IsString(), c.IsEnum(), c.IsTime():
+		return d.QuoteString(c.Default)
+	}
+	return c.Default
+}
+
+// parseDefault returns the default value that the database reports in the
+// form of SQL, such as "'abc'", "20" and "CURRENT_TIMESTAMP". The others
+// than the string literals, the booleans and the numbers are the
+// expressions. NULL is the same as no default value.
+func parseDefault(def string) (value string, expr bool) {
+	def = strings.TrimSpace(def)
+	if m := quotedStringRegexp.FindStringSubmatch(def); m != nil {
+		return strings.Replace(m[1], "''", "'", -1), false
+	}
+	if strings.EqualFold(def, "NULL") || def == "" {
+		return "", false
+	}
+	if strings.EqualFold(def, "TRUE") || strings.EqualFold(def, "FALSE") {
+		return def, false
+	}
+	if number := strings.Trim(def, "()"); numberRegexp.MatchString(number) {
+		return number, false
+	}
+	return schema.NormalizeDefaultExpr(def), true
+}
+
+// generatedSQL returns the definition of the generated column.
+func generatedSQL(c *schema.Column) string {
+	if c.Stored {
+		return fmt.Sprintf("GENERATED ALWAYS AS (%s) STORED", c.Generated)
+	}
+	return fmt.Sprintf("GENERATED ALWAYS AS (%s) VIRTUAL", c.Generated)
+}
+
+// recreatedIndexes returns the indexes of the expected table that aren't
+// changed but have any of the columns to be dropped and added again.
+func recreatedIndexes(diff *schema.TableDiff, columns []string) []*schema.Index {
+	recreated := make(map[string]bool, len(columns))
+	for _, name := range columns {
+		recreated[name] = true
+	}
+	var indexes []*schema.Index
+	for _, index := range diff.Expected.Indexes {
+		if !reflect.DeepEqual(diff.Current.Index(index.Name), index) {
+			continue
+		}
+		for _, name := range index.Columns {
+			if recreated[name] {
+				indexes = append(indexes, index)
+				break
+			}
+		}
+	}
+	return indexes
+}
+
+func quoteNames(d Dialect, names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = d.Quote(name)
+	}
+	return strings.Join(quoted, ",")
+}
+
+// inlinePrimaryKey returns the column name if the primary key of the table
+// consists of a single column. The primary key on a single column is declared
+// in the column definition.
+func inlinePrimaryKey(t *schema.Table) string {
+	if pk := t.PrimaryKey(); pk != nil && len(pk.Columns) == 1 {
+		return pk.Columns[0]
+	}
+	return ""
+}
+
+// createIndexSQL returns CREATE INDEX statement for the dialects that don't
+// support the index definition in CREATE TABLE.
+func createIndexSQL(d Dialect, tableName string, index *schema.Index) string {
+	keyType := "INDEX"
+	if index.Unique {
+		keyType = "UNIQUE INDEX"
+	}
+	return fmt.Sprintf("CREATE %s %s ON %s (%s)", keyType, d.Quote(index.Name), d.Quote(tableName), quoteNames(d, index.Columns))
+}
+
+// uniqueIndexColumn returns the column that the index makes unique alone, or
+// nil if the index isn't so.
+func uniqueIndexColumn(t *schema.Table, index *schema.Index) *schema.Column {
+	if !index.Unique || index.IsPrimaryKey() || len(index.Columns) != 1 {
+		return nil
+	}
+	return t.Column(index.Columns[0])
+}
+
+// renamedColumns returns the map of the old name to the new name of the
+// renamed columns.
+func renamedColumns(diff *schema.TableDiff) map[string]string {
+	renames := make(map[string]string, len(diff.RenameColumns))
+	for _, r := range diff.RenameColumns {
+		renames[r.Current.Name] = r.Expected.Name
+	}
+	return renames
+}
+
+// foreignKeySQL returns the definition of the foreign key constraint that is
+// used in both of CREATE TABLE and ALTER TABLE ADD.
+func foreignKeySQL(d Dialect, fk *schema.ForeignKey) string {
+	def := fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+		d.Quote(fk.Name), quoteNames(d, fk.Columns), d.Quote(fk.ReferencedTable), quoteNames(d, fk.ReferencedColumns))
+	if fk.OnDelete != "" {
+		def += " ON DELETE " + fk.OnDelete
+	}
+	if fk.OnUpdate != "" {
+		def += " ON UPDATE " + fk.OnUpdate
+	}
+	return def
+}
@@ -0,0 +1,176 @@
+package migu
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/astronoka/migu/dialect"
+	"github.com/astronoka/migu/schema"
+)
+
+// DiffSchema returns SQLs to migrate the current schema to the expected
+// schema. It compares the schemas without accessing the database.
+func DiffSchema(d dialect.Dialect, current, expected *schema.Schema) []string {
+	return Queries(PlanSchema(d, current, expected))
+}
+
+func diffTable(d dialect.Dialect, current, expected *schema.Table) *schema.TableDiff {
+	diff := &schema.TableDiff{
+		Current:  current,
+		Expected: expected,
+	}
+	renames := map[string]string{}
+	for _, column := range expected.Columns {
+		currentColumn := current.Column(column.Name)
+		if currentColumn == nil && column.RenamedFrom != "" && expected.Column(column.RenamedFrom) == nil {
+			if currentColumn = current.Column(column.RenamedFrom); currentColumn != nil {
+				diff.RenameColumns = append(diff.RenameColumns, &schema.ColumnDiff{
+					Current:  currentColumn,
+					Expected: column,
+				})
+				renames[currentColumn.Name] = column.Name
+			}
+		}
+		switch {
+		case currentColumn == nil:
+			diff.AddColumns = append(diff.AddColumns, column)
+		case hasColumnDifference(d, current, currentColumn, column), hasCollationDifference(d, current, currentColumn, column):
+			diff.ModifyColumns = append(diff.ModifyColumns, &schema.ColumnDiff{
+				Current:  currentColumn,
+				Expected: column,
+			})
+		}
+	}
+	for _, column := range current.Columns {
+		if _, renamed := renames[column.Name]; !renamed && expected.Column(column.Name) == nil {
+			diff.DropColumns = append(diff.DropColumns, column)
+		}
+	}
+	for _, index := range current.Indexes {
+		if !reflect.DeepEqual(renameIndexColumns(index, renames), expected.Index(index.Name)) {
+			diff.DropIndexes = append(diff.DropIndexes, index)
+		}
+	}
+	for _, index := range expected.Indexes {
+		currentIndex := current.Index(index.Name)
+		if currentIndex == nil || !reflect.DeepEqual(index, renameIndexColumns(currentIndex, renames)) {
+			diff.AddIndexes = append(diff.AddIndexes, index)
+		}
+	}
+	currentForeignKeys := make([]*schema.ForeignKey, len(current.ForeignKeys))
+	for i, fk := range current.ForeignKeys {
+		currentForeignKeys[i] = renameForeignKeyColumns(fk, renames)
+		if !hasForeignKey(expected.ForeignKeys, currentForeignKeys[i]) {
+			diff.DropForeignKeys = append(diff.DropForeignKeys, fk)
+		}
+	}
+	for _, fk := range expected.ForeignKeys {
+		if !hasForeignKey(currentForeignKeys, fk) {
+			diff.AddForeignKeys = append(diff.AddForeignKeys, fk)
+		}
+	}
+	return diff
+}
+
+// renameIndexColumns returns the index that the columns are renamed.
+// The renamed columns of the index are renamed by the database as well.
+func renameIndexColumns(index *schema.Index, renames map[string]string) *schema.Index {
+	if len(renames) == 0 {
+		return index
+	}
+	renamed := *index
+	renamed.Columns = make([]string, len(index.Columns))
+	for i, name := range index.Columns {
+		if newName, ok := renames[name]; ok {
+			name = newName
+		}
+		renamed.Columns[i] = name
+	}
+	return &renamed
+}
+
+// renameForeignKeyColumns returns the foreign key that the columns are
+// renamed as well as renameIndexColumns.
+func renameForeignKeyColumns(fk *schema.ForeignKey, renames map[string]string) *schema.ForeignKey {
+	if len(renames) == 0 {
+		return fk
+	}
+	renamed := *fk
+	renamed.Columns = make([]string, len(fk.Columns))
+	for i, name := range fk.Columns {
+		if newName, ok := renames[name]; ok {
+			name = newName
+		}
+		renamed.Columns[i] = name
+	}
+	return &renamed
+}
+
+// hasForeignKey reports whether fks has the same foreign key as fk.
+// The foreign keys are compared by the definitions regardless of the names,
+// because the names of the implicit constraints depend on the database.
+func hasForeignKey(fks []*schema.ForeignKey, fk *schema.ForeignKey) bool {
+	for _, k := range fks {
+		if reflect.DeepEqual(k.Columns, fk.Columns) &&
+			k.ReferencedTable == fk.ReferencedTable &&
+			reflect.DeepEqual(k.ReferencedColumns, fk.ReferencedColumns) &&
+			foreignKeyAction(k.OnDelete) == foreignKeyAction(fk.OnDelete) &&
+			foreignKeyAction(k.OnUpdate) == foreignKeyAction(fk.OnUpdate) {
+			return true
+		}
+	}
+	return false
+}
+
+// foreignKeyAction returns the referential action in upper case. RESTRICT
+// and NO ACTION are the default of the databases, so these are the empty
+// string.
+func foreignKeyAction(action string) string {
+	switch action = strings.ToUpper(action); action {
+	case "RESTRICT", "NO ACTION":
+		return ""
+	}
+	return action
+}
+
+// columnTypeInTable returns the type of the column in the table in the
+// dialect. The type may depend on the character set, such as the maximum
+// length of VARCHAR in MySQL, so the column without the character set has
+// the one of the table.
+func columnTypeInTable(d dialect.Dialect, table *schema.Table, c *schema.Column) string {
+	collator, ok := d.(dialect.Collator)
+	if !ok {
+		return d.ColumnType(c)
+	}
+	column := *c
+	column.Charset, _ = collator.ColumnCollation(table, c)
+	return d.ColumnType(&column)
+}
+
+// hasCollationDifference reports whether the character sets or the
+// collations of the columns in the current table are different. Any
+// collation of the character set is the same as the column that has only the
+// character set.
+func hasCollationDifference(d dialect.Dialect, table *schema.Table, current, expected *schema.Column) bool {
+	collator, ok := d.(dialect.Collator)
+	if !ok {
+		return false
+	}
+	currentCharset, currentCollation := collator.ColumnCollation(table, current)
+	expectedCharset, expectedCollation := collator.ColumnCollation(table, expected)
+	return currentCharset != expectedCharset || (expectedCollation != "" && currentCollation != expectedCollation)
+}
+
+// hasColumnDifference reports whether the columns in the current table are
+// different. The types are compared in the form of the dialect because the
+// dialect may map the different types into the same type.
+func hasColumnDifference(d dialect.Dialect, table *schema.Table, current, expected *schema.Column) bool {
+	return columnTypeInTable(d, table, current) != columnTypeInTable(d, table, expected) ||
+		current.Nullable != expected.Nullable ||
+		!current.HasSameDefault(expected) ||
+		current.OnUpdate != expected.OnUpdate ||
+		!current.HasSameGenerated(expected) ||
+		current.AutoIncrement != expected.AutoIncrement ||
+		current.Unique != expected.Unique ||
+		current.Comment != expected.Comment
+}
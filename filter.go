@@ -0,0 +1,67 @@
+package migu
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/astronoka/migu/schema"
+)
+
+// filterTables returns the schema that has only the tables managed by Migu.
+// The table is managed if it matches any of the include patterns (or there are
+// no include patterns), and it doesn't match any of the exclude patterns.
+func (o *option) filterTables(s *schema.Schema) (*schema.Schema, error) {
+	if len(o.includes) == 0 && len(o.excludes) == 0 {
+		return s, nil
+	}
+	filtered := &schema.Schema{Charset: s.Charset}
+	for _, table := range s.Tables {
+		included := len(o.includes) == 0
+		for _, pattern := range o.includes {
+			ok, err := matchTableName(pattern, table.Name)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				included = true
+				break
+			}
+		}
+		for _, pattern := range o.excludes {
+			if !included {
+				break
+			}
+			ok, err := matchTableName(pattern, table.Name)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				included = false
+			}
+		}
+		if included {
+			filtered.Tables = append(filtered.Tables, table)
+		}
+	}
+	return filtered, nil
+}
+
+// matchTableName reports whether the table name matches the pattern.
+// The pattern enclosed in slashes (e.g. /^_.*_(gho|del)$/) is a regular
+// expression, otherwise it's a glob pattern of path.Match (e.g. schema_*).
+func matchTableName(pattern, name string) (bool, error) {
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return false, fmt.Errorf("migu: invalid table pattern %s. %v", pattern, err)
+		}
+		return re.MatchString(name), nil
+	}
+	ok, err := path.Match(pattern, name)
+	if err != nil {
+		return false, fmt.Errorf("migu: invalid table pattern %s. %v", pattern, err)
+	}
+	return ok, nil
+}
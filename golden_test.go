@@ -0,0 +1,93 @@
+package migu_test
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/astronoka/migu"
+	"github.com/astronoka/migu/dialect"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// TestDiffGolden compares the SQLs to migrate old.go to new.go in each
+// directory of testdata/plan with the golden file of each dialect.
+// The directory that has old.sql instead of old.go is the snapshot, and is
+// tested only in the dialects that can read it.
+// Run with -update to rewrite the golden files.
+func TestDiffGolden(t *testing.T) {
+	dirs, err := filepath.Glob(filepath.Join("testdata", "plan", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) == 0 {
+		t.Fatal("no test cases in testdata/plan")
+	}
+	for _, dir := range dirs {
+		for _, v := range []struct {
+			name    string
+			dialect dialect.Dialect
+		}{
+			{"mysql", &dialect.MySQL{}},
+			{"postgres", &dialect.PostgreSQL{}},
+			{"sqlite3", &dialect.SQLite3{}},
+		} {
+			dir, v := dir, v
+			t.Run(filepath.Base(dir)+"/"+v.name, func(t *testing.T) {
+				if _, ok := v.dialect.(dialect.DDLParser); !ok && isSnapshotGolden(dir) {
+					t.Skip("the dialect doesn't support the snapshot")
+				}
+				actual := diffGolden(t, dir, v.dialect)
+				// The plan must be the same every time.
+				for i := 0; i < 3; i++ {
+					if again := diffGolden(t, dir, v.dialect); !bytes.Equal(again, actual) {
+						t.Fatalf("the plan isn't stable:\n%s\nvs\n%s", actual, again)
+					}
+				}
+				golden := filepath.Join(dir, v.name+".sql")
+				if *update {
+					if err := ioutil.WriteFile(golden, actual, 0644); err != nil {
+						t.Fatal(err)
+					}
+				}
+				expect, err := ioutil.ReadFile(golden)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(actual, expect) {
+					t.Errorf("migu.DiffFiles(%s) =>\n%s\nwant\n%s", dir, actual, expect)
+				}
+			})
+		}
+	}
+}
+
+func isSnapshotGolden(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "old.sql"))
+	return err == nil
+}
+
+func diffGolden(t *testing.T, dir string, d dialect.Dialect) []byte {
+	var (
+		sqls []string
+		err  error
+	)
+	if isSnapshotGolden(dir) {
+		sqls, err = migu.Diff(nil, filepath.Join(dir, "new.go"), nil, migu.WithDialect(d), migu.WithSnapshot(filepath.Join(dir, "old.sql")))
+	} else {
+		sqls, err = migu.DiffFiles(filepath.Join(dir, "old.go"), nil, filepath.Join(dir, "new.go"), nil, migu.WithDialect(d))
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	for _, sql := range sqls {
+		fmt.Fprintf(&buf, "%s;\n", sql)
+	}
+	return buf.Bytes()
+}
@@ -4,27 +4,57 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"reflect"
 	"strings"
 
 	"github.com/astronoka/migu/dialect"
+	"github.com/astronoka/migu/schema"
 )
 
-// Index is table index
-type Index struct {
-	Name        string
-	Unique      bool
-	ColumnNames []string
-}
-
-func (index *Index) isPrimaryKey() bool {
-	return strings.ToUpper(index.Name) == "PRIMARY"
-}
-
-func (index *Index) isUniqueKey() bool {
-	return index.Unique && !index.isPrimaryKey()
+func parseIndexStructTag(tag reflect.StructTag) (*schema.Index, error) {
+	migu := tag.Get("migu")
+	if migu == "" {
+		return nil, fmt.Errorf("migu: parseIndexStructTag: index tag must not be empty")
+	}
+	index := &schema.Index{}
+	isPrimaryKey := false
+	for _, opt := range strings.Split(migu, tagSeparater) {
+		optval := strings.SplitN(opt, ":", 2)
+		if len(optval) < 1 {
+			return nil, fmt.Errorf("migu: parseIndexStructTag: 'migu' tag must specify values")
+		}
+		switch optval[0] {
+		case tagPrimaryKey:
+			isPrimaryKey = true
+		case tagIndex:
+			if len(optval) < 2 {
+				return nil, fmt.Errorf("migu: parseIndexStructTag: '%s' tag must specify parameters", tagIndex)
+			}
+			params := strings.SplitN(optval[1], ",", -1)
+			if len(params) < 1 {
+				return nil, fmt.Errorf("migu: parseIndexStructTag: '%s' tag must specify one column at least", tagIndex)
+			}
+			if len(params) == 1 {
+				index.Name = params[0]
+				index.Columns = params
+			} else {
+				index.Name = params[0]
+				index.Columns = params[1:]
+			}
+		case tagUnique:
+			index.Unique = true
+		default:
+			return nil, fmt.Errorf("migu: parseIndexStructTag: unknown option: `%s'", opt)
+		}
+	}
+	if isPrimaryKey {
+		index.Name = schema.PrimaryKeyName
+		index.Unique = true
+	}
+	return index, nil
 }
 
-func (index *Index) AsASTField(indexNo int) (*ast.Field, error) {
+func indexASTField(index *schema.Index, indexNo int) (*ast.Field, error) {
 	field := &ast.Field{
 		Names: []*ast.Ident{
 			ast.NewIdent(fmt.Sprintf("Index%02d", indexNo)),
@@ -32,14 +62,13 @@ func (index *Index) AsASTField(indexNo int) (*ast.Field, error) {
 		Type: ast.NewIdent("interface{}"),
 	}
 	var tags []string
-	if index.isPrimaryKey() {
+	if index.IsPrimaryKey() {
 		tags = append(tags, tagPrimaryKey)
-	}
-	if index.isUniqueKey() {
+	} else if index.Unique {
 		tags = append(tags, tagUnique)
 	}
-	if len(index.ColumnNames) > 0 {
-		tags = append(tags, tagIndex+":"+index.Name+","+strings.Join(index.ColumnNames, ","))
+	if len(index.Columns) > 0 {
+		tags = append(tags, tagIndex+":"+index.Name+","+strings.Join(index.Columns, ","))
 	}
 	if len(tags) > 0 {
 		field.Tag = &ast.BasicLit{
@@ -50,16 +79,49 @@ func (index *Index) AsASTField(indexNo int) (*ast.Field, error) {
 	return field, nil
 }
 
+// Index is the definition of the index.
+//
+// Deprecated: Use schema.Index instead.
+type Index struct {
+	Name        string
+	Unique      bool
+	ColumnNames []string
+}
+
+func newIndex(index *schema.Index) *Index {
+	return &Index{
+		Name:        index.Name,
+		Unique:      index.Unique,
+		ColumnNames: index.Columns,
+	}
+}
+
+func (index *Index) schemaIndex() *schema.Index {
+	return &schema.Index{
+		Name:    index.Name,
+		Unique:  index.Unique,
+		Columns: index.ColumnNames,
+	}
+}
+
+func (index *Index) AsASTField(indexNo int) (*ast.Field, error) {
+	return indexASTField(index.schemaIndex(), indexNo)
+}
+
+// AsCreateTableDefinition returns the definition of the index in the CREATE
+// TABLE statement of MySQL.
+//
+// Deprecated: Use the CreateTable method of dialect.Dialect instead.
 func (index *Index) AsCreateTableDefinition(d dialect.Dialect) string {
 	quotedNames := make([]string, 0, len(index.ColumnNames))
 	for _, name := range index.ColumnNames {
 		quotedNames = append(quotedNames, d.Quote(name))
 	}
-	if index.isPrimaryKey() {
+	if index.schemaIndex().IsPrimaryKey() {
 		return fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(quotedNames, ","))
 	}
 	keyType := "INDEX"
-	if index.isUniqueKey() {
+	if index.Unique {
 		keyType = "UNIQUE"
 	}
 	return fmt.Sprintf("%s %s (%s)", keyType, d.Quote(index.Name), strings.Join(quotedNames, ","))
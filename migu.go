@@ -8,12 +8,16 @@ import (
 	"go/parser"
 	"go/token"
 	"io"
+	"io/ioutil"
 	"reflect"
 	"sort"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/astronoka/migu/dialect"
+	"github.com/astronoka/migu/schema"
 	"github.com/azer/snakecase"
 	"github.com/naoina/go-stringutil"
 )
@@ -29,17 +33,32 @@ import (
 // All query for synchronization will be performed within the transaction if
 // storage engine supports the transaction. (e.g. MySQL's MyISAM engine does
 // NOT support the transaction)
-func Sync(db *sql.DB, filename string, src interface{}) error {
-	sqls, err := Diff(db, filename, src)
+func Sync(db *sql.DB, filename string, src interface{}, opts ...Option) error {
+	sqls, err := Diff(db, filename, src, opts...)
 	if err != nil {
 		return err
 	}
+	return Exec(db, sqls, nil, opts...)
+}
+
+// Exec executes the queries in a transaction by the dialect of opts.
+// If exec isn't nil, it's called to execute each query instead of tx.Exec.
+func Exec(db *sql.DB, queries []string, exec func(tx *sql.Tx, query string) error, opts ...Option) error {
+	if exec == nil {
+		exec = func(tx *sql.Tx, query string) error {
+			_, err := tx.Exec(query)
+			return err
+		}
+	}
+	if m, ok := newOption(opts).dialect.(dialect.Migrator); ok {
+		return m.Migrate(db, queries, exec)
+	}
 	tx, err := db.Begin()
 	if err != nil {
 		return err
 	}
-	for _, sql := range sqls {
-		if _, err := tx.Exec(sql); err != nil {
+	for _, query := range queries {
+		if err := exec(tx, query); err != nil {
 			tx.Rollback()
 			return err
 		}
@@ -48,40 +67,46 @@ func Sync(db *sql.DB, filename string, src interface{}) error {
 }
 
 // Diff returns SQLs for schema synchronous between database and Go's struct.
-func Diff(db *sql.DB, filename string, src interface{}) ([]string, error) {
-	expectedTableASTMap, err := makeTableASTMap(filename, src)
+func Diff(db *sql.DB, filename string, src interface{}, opts ...Option) ([]string, error) {
+	ops, err := Plan(db, filename, src, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("migu: Diff error. " + err.Error())
+		return nil, err
 	}
-	currentTableMap, err := getAllTables(db)
+	if err := newOption(opts).check(ops); err != nil {
+		return nil, err
+	}
+	return Queries(ops), nil
+}
+
+// Plan returns the operations for schema synchronous between database and
+// Go's struct. The source is provided in the same way as Diff.
+func Plan(db *sql.DB, filename string, src interface{}, opts ...Option) ([]*Op, error) {
+	o := newOption(opts)
+	expected, err := parseSchema(filename, src, o)
 	if err != nil {
-		return nil, fmt.Errorf("migu: Diff error. " + err.Error())
-	}
-	d := &dialect.MySQL{}
-	var migrations []string
-	for _, name := range sortTableASTNames(expectedTableASTMap) {
-		tableAST := expectedTableASTMap[name]
-		currentTable, alreadyExist := currentTableMap[name]
-		if !alreadyExist {
-			queries, err := tableAST.CreateTableQuery(d)
-			if err != nil {
-				return nil, fmt.Errorf("migu: Diff error. " + err.Error())
-			}
-			migrations = append(migrations, queries...)
-		} else {
-			queries, err := tableAST.AlterTableQueries(d, currentTable)
-			if err != nil {
-				return nil, fmt.Errorf("migu: Diff error. " + err.Error())
-			}
-			migrations = append(migrations, queries...)
-		}
-		delete(expectedTableASTMap, name)
-		delete(currentTableMap, name)
+		return nil, fmt.Errorf("migu: Plan error. " + err.Error())
+	}
+	current, err := inspectSchema(db, o)
+	if err != nil {
+		return nil, fmt.Errorf("migu: Plan error. " + err.Error())
+	}
+	return PlanSchema(o.dialect, current, expected), nil
+}
+
+// DiffFiles returns SQLs to migrate the schema of Go's structs from the old
+// source to the new source without the database.
+// Each source is provided in the same way as Diff.
+func DiffFiles(oldFilename string, oldSrc interface{}, newFilename string, newSrc interface{}, opts ...Option) ([]string, error) {
+	o := newOption(opts)
+	current, err := parseSchema(oldFilename, oldSrc, o)
+	if err != nil {
+		return nil, fmt.Errorf("migu: DiffFiles error. " + err.Error())
 	}
-	for name := range currentTableMap {
-		migrations = append(migrations, fmt.Sprintf(`DROP TABLE %s`, d.Quote(toSchemaTableName(name))))
+	expected, err := parseSchema(newFilename, newSrc, o)
+	if err != nil {
+		return nil, fmt.Errorf("migu: DiffFiles error. " + err.Error())
 	}
-	return migrations, nil
+	return DiffSchema(o.dialect, current, expected), nil
 }
 
 type field struct {
@@ -93,7 +118,35 @@ type field struct {
 	AutoIncrement bool
 	Ignore        bool
 	Default       string
+	DefaultExpr   bool
 	Size          uint64
+	Precision     uint64
+	Scale         uint64
+	TimeType      string
+	SQLType       string
+	RenamedFrom   string
+
+	// EnumType is ENUM or SET, and Values is its values.
+	EnumType string
+	Values   []string
+
+	Charset   string
+	Collation string
+
+	// UpdateExpr is the expression to set on update such as
+	// CURRENT_TIMESTAMP.
+	UpdateExpr string
+
+	// Generated is the expression of the generated column, and Stored
+	// reports whether it's STORED instead of VIRTUAL.
+	Generated string
+	Stored    bool
+
+	// The referenced table and column of the foreign key, and its actions.
+	ReferencedTable  string
+	ReferencedColumn string
+	OnDelete         string
+	OnUpdate         string
 }
 
 func newField(typeName string, f *ast.Field) (*field, error) {
@@ -109,13 +162,57 @@ func newField(typeName string, f *ast.Field) (*field, error) {
 			return nil, err
 		}
 	}
-	if isSizeRequiredType(ret.Type) {
+	if ret.Ignore {
+		return ret, nil
+	}
+	if ret.SQLType != "" && (ret.Size > 0 || ret.Precision > 0 || ret.Scale > 0 || ret.TimeType != "" || ret.EnumType != "") {
+		return nil, fmt.Errorf("`%s' tag can't be used with the other tags of the type", tagType)
+	}
+	switch {
+	case isSizeRequiredType(ret.Type):
 		if ret.Size == 0 {
 			ret.Size = 255
 		}
-	} else {
+	case isBinaryType(ret.Type):
+		// the size of the binary types is optional.
+	default:
 		ret.Size = 0
 	}
+	c, err := ret.column()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case c.Type == "DECIMAL":
+		if c.Scale > c.Precision {
+			return nil, fmt.Errorf("`%s' must not be greater than `%s' (%d > %d)", tagScale, tagPrecision, c.Scale, c.Precision)
+		}
+	case c.HasFractionalSeconds():
+		if ret.Scale > 0 {
+			return nil, fmt.Errorf("`%s' tag isn't available for %s", tagScale, c.Type)
+		}
+		if c.Precision > 6 {
+			return nil, fmt.Errorf("`%s' of %s must be 6 or less", tagPrecision, c.Type)
+		}
+	case c.IsTime() && ret.Precision > 0:
+		return nil, fmt.Errorf("`%s' tag isn't available for %s", tagPrecision, c.Type)
+	}
+	if !c.IsString() && !c.IsEnum() && (ret.Charset != "" || ret.Collation != "") {
+		return nil, fmt.Errorf("`%s' and `%s' tags aren't available for %s", tagCharset, tagCollate, c.Type)
+	}
+	if c.Type != "DECIMAL" && !c.IsTime() && (ret.Precision > 0 || ret.Scale > 0) {
+		return nil, fmt.Errorf("`%s' and `%s' tags aren't available for %s; use the `%s' tag", tagPrecision, tagScale, c.Type, tagType)
+	}
+	switch {
+	case ret.Stored && ret.Generated == "":
+		return nil, fmt.Errorf("`%s' tag must be used with `%s' tag", tagStored, tagGenerated)
+	case ret.Generated != "" && (ret.Default != "" || ret.UpdateExpr != "" || ret.AutoIncrement):
+		return nil, fmt.Errorf("`%s' tag can't be used with `%s', `%s' and `%s' tags", tagGenerated, tagDefault, tagOnUpdate, tagAutoIncrement)
+	case c.DefaultExpr && c.Default == "NULL" && !c.Nullable:
+		return nil, fmt.Errorf("`%s' tag can't be NULL for the NOT NULL column", tagDefault)
+	case c.OnUpdate != "" && c.Type != "DATETIME" && c.Type != "TIMESTAMP":
+		return nil, fmt.Errorf("`%s' tag of %s isn't available for %s", tagOnUpdate, c.OnUpdate, c.Type)
+	}
 	if f.Comment != nil {
 		ret.Comment = strings.TrimSpace(f.Comment.Text())
 	}
@@ -123,37 +220,83 @@ func newField(typeName string, f *ast.Field) (*field, error) {
 }
 
 // Fprint generates Go's structs from database schema and writes to output.
-func Fprint(output io.Writer, db *sql.DB) error {
-	tableMap, err := getAllTables(db)
+func Fprint(output io.Writer, db *sql.DB, opts ...Option) error {
+	o := newOption(opts)
+	current, err := inspectSchema(db, o)
+	if err != nil {
+		return err
+	}
+	pkgs, err := importPackages(current, o.nullTypes)
 	if err != nil {
 		return err
 	}
-	if hasDatetimeColumn(tableMap) {
-		if err := fprintln(output, importAST("time")); err != nil {
+	for _, pkg := range pkgs {
+		if err := fprintln(output, importAST(pkg)); err != nil {
 			return err
 		}
 	}
-	names := make([]string, 0, len(tableMap))
-	for name := range tableMap {
-		names = append(names, name)
-	}
-	sort.Strings(names)
-	for _, name := range names {
-		table := tableMap[name]
-		err := fprintTable(output, name, table)
+	for _, table := range current.Tables {
+		err := fprintEnums(output, table)
 		if err != nil {
 			return err
 		}
-		err = fprintIndex(output, name, table)
+		err = fprintTable(output, table, o.nullTypes)
 		if err != nil {
 			return err
 		}
+		err = fprintIndex(output, table)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FprintSQL writes the CREATE TABLE statements of the database schema to
+// output in the SQL dialect.
+func FprintSQL(output io.Writer, db *sql.DB, opts ...Option) error {
+	o := newOption(opts)
+	current, err := inspectSchema(db, o)
+	if err != nil {
+		return err
+	}
+	return fprintSQL(output, o.dialect, current)
+}
+
+// FprintFileSQL writes the CREATE TABLE statements of Go's structs to output
+// in the SQL dialect.
+// The source is provided in the same way as Diff.
+func FprintFileSQL(output io.Writer, filename string, src interface{}, opts ...Option) error {
+	o := newOption(opts)
+	s, err := parseSchema(filename, src, o)
+	if err != nil {
+		return fmt.Errorf("migu: FprintFileSQL error. " + err.Error())
+	}
+	return fprintSQL(output, o.dialect, s)
+}
+
+func fprintSQL(output io.Writer, d dialect.Dialect, s *schema.Schema) error {
+	for _, table := range sortTablesByDependency(s.Tables) {
+		for _, sql := range d.CreateTable(table) {
+			if _, err := fmt.Fprintf(output, "%s;\n\n", sql); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func fprintEnums(output io.Writer, table *schema.Table) error {
+	for _, decl := range enumDecls(table) {
+		if err := fprintln(output, decl); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-func fprintTable(output io.Writer, name string, table *Table) error {
-	s, err := structAST(name, table)
+func fprintTable(output io.Writer, table *schema.Table, nullTypes bool) error {
+	s, err := structAST(table, nullTypes)
 	if err != nil {
 		return err
 	}
@@ -163,8 +306,8 @@ func fprintTable(output io.Writer, name string, table *Table) error {
 	return nil
 }
 
-func fprintIndex(output io.Writer, tableName string, table *Table) error {
-	s, err := indexStructAST(tableName, table.Indexes)
+func fprintIndex(output io.Writer, table *schema.Table) error {
+	s, err := indexStructAST(table)
 	if err != nil {
 		return err
 	}
@@ -176,165 +319,70 @@ func fprintIndex(output io.Writer, tableName string, table *Table) error {
 
 const (
 	tagDefault       = "default"
+	tagDefaultExpr   = "defaultexpr"
 	tagPrimaryKey    = "pk"
 	tagAutoIncrement = "autoincrement"
 	tagUnique        = "unique"
 	tagSize          = "size"
+	tagPrecision     = "precision"
+	tagScale         = "scale"
+	tagDate          = "date"
+	tagTime          = "time"
+	tagTimestamp     = "timestamp"
+	tagYear          = "year"
+	tagType          = "type"
+	tagEnum          = "enum"
+	tagSet           = "set"
+	tagCharset       = "charset"
+	tagCollate       = "collate"
+	tagGenerated     = "generated"
+	tagStored        = "stored"
 	tagIndex         = "index"
+	tagRename        = "rename"
+	tagForeignKey    = "fk"
+	tagOnDelete      = "ondelete"
+	tagOnUpdate      = "onupdate"
 	tagIgnore        = "-"
 	tagSeparater     = ";"
 )
 
-func getAllTables(db *sql.DB) (map[string]*Table, error) {
-	dbname, err := getCurrentDBName(db)
-	if err != nil {
-		return nil, err
+// inspectSchema returns the current schema from the database, or from the
+// snapshot if specified. It has only the tables managed by Migu.
+func inspectSchema(db *sql.DB, o *option) (*schema.Schema, error) {
+	if o.snapshot != "" {
+		s, err := readSnapshot(o.dialect, o.snapshot)
+		if err != nil {
+			return nil, err
+		}
+		return o.filterTables(s)
 	}
-	tableColumns, err := getTableColumns(db, dbname)
+	tables, err := o.dialect.Tables(db)
 	if err != nil {
 		return nil, fmt.Errorf("migu: get table map failed. " + err.Error())
 	}
-	tables := make(map[string]*Table)
-	for tableName, columns := range tableColumns {
-		tables[tableName] = &Table{
-			Columns: columns,
+	s := &schema.Schema{Tables: tables}
+	if i, ok := o.dialect.(dialect.CharsetInspector); ok {
+		if s.Charset, err = i.DefaultCharset(db); err != nil {
+			return nil, fmt.Errorf("migu: get default charset failed. " + err.Error())
 		}
 	}
+	return o.filterTables(s)
+}
 
-	indexMap, err := getIndexMap(db, dbname)
-	if err != nil {
-		return nil, err
+func readSnapshot(d dialect.Dialect, filename string) (*schema.Schema, error) {
+	parser, ok := d.(dialect.DDLParser)
+	if !ok {
+		return nil, fmt.Errorf("migu: the dialect doesn't support the snapshot")
 	}
-	for tableName, table := range tables {
-		indexes, exist := indexMap[tableName]
-		if !exist {
-			continue
-		}
-		for _, index := range indexes {
-			table.Indexes = append(table.Indexes, index)
-		}
-	}
-
-	return tables, nil
-}
-
-func getTableColumns(db *sql.DB, dbname string) (map[string][]*columnSchema, error) {
-	query := `
-SELECT
-  TABLE_NAME,
-  COLUMN_NAME,
-  COLUMN_DEFAULT,
-  IS_NULLABLE,
-  DATA_TYPE,
-  CHARACTER_MAXIMUM_LENGTH,
-  CHARACTER_OCTET_LENGTH,
-  NUMERIC_PRECISION,
-  NUMERIC_SCALE,
-  COLUMN_TYPE,
-  COLUMN_KEY,
-  EXTRA,
-  COLUMN_COMMENT
-FROM information_schema.COLUMNS
-WHERE TABLE_SCHEMA = ?
-ORDER BY TABLE_NAME, ORDINAL_POSITION`
-	rows, err := db.Query(query, dbname)
+	b, err := ioutil.ReadFile(filename)
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	tableColumns := map[string][]*columnSchema{}
-	for rows.Next() {
-		schema := &columnSchema{}
-		if err := rows.Scan(
-			&schema.TableName,
-			&schema.ColumnName,
-			&schema.ColumnDefault,
-			&schema.IsNullable,
-			&schema.DataType,
-			&schema.CharacterMaximumLength,
-			&schema.CharacterOctetLength,
-			&schema.NumericPrecision,
-			&schema.NumericScale,
-			&schema.ColumnType,
-			&schema.ColumnKey,
-			&schema.Extra,
-			&schema.ColumnComment,
-		); err != nil {
-			return nil, err
-		}
-		tableColumns[schema.TableName] = append(tableColumns[schema.TableName], schema)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("migu: read snapshot failed. " + err.Error())
 	}
-	return tableColumns, nil
-}
-
-func getCurrentDBName(db *sql.DB) (string, error) {
-	var dbname sql.NullString
-	err := db.QueryRow(`SELECT DATABASE()`).Scan(&dbname)
-	return dbname.String, err
-}
-
-func getIndexMap(db *sql.DB, dbname string) (map[string]map[string]*Index, error) {
-	query := `
-SELECT
-  TABLE_NAME,
-  NON_UNIQUE,
-  INDEX_NAME,
-  SEQ_IN_INDEX,
-  COLUMN_NAME,
-  COLLATION
-FROM information_schema.STATISTICS
-WHERE TABLE_SCHEMA = ?
-ORDER BY
-  TABLE_NAME,
-  INDEX_NAME,
-  SEQ_IN_INDEX
-`
-	rows, err := db.Query(query, dbname)
+	tables, err := parser.ParseDDL(string(b))
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	// map[TABLE_NAME][INDEX_NAME]index
-	// https://dev.mysql.com/doc/refman/5.6/en/show-index.html
-	indexMap := make(map[string]map[string]*Index)
-	for rows.Next() {
-		var (
-			tableName  string
-			nonUnique  int64
-			indexName  string
-			seqInIndex int64
-			columnName string
-			collation  *string
-		)
-		if err := rows.Scan(&tableName, &nonUnique, &indexName, &seqInIndex, &columnName, &collation); err != nil {
-			return nil, err
-		}
-		if _, exists := indexMap[tableName]; !exists {
-			indexMap[tableName] = make(map[string]*Index)
-		}
-		if _, exists := indexMap[tableName][indexName]; !exists {
-			indexMap[tableName][indexName] = &Index{
-				Unique:      nonUnique == 0,
-				Name:        indexName,
-				ColumnNames: []string{},
-			}
-		}
-		index := indexMap[tableName][indexName]
-		index.ColumnNames = append(index.ColumnNames, columnName)
-	}
-	return indexMap, rows.Err()
-}
-
-func formatDefault(d dialect.Dialect, t, def string) string {
-	switch t {
-	case "string":
-		return d.QuoteString(def)
-	default:
-		return def
+		return nil, fmt.Errorf("migu: parse snapshot failed. %s: %v", filename, err)
 	}
+	return &schema.Schema{Tables: tables}, nil
 }
 
 func fprintln(output io.Writer, decl ast.Decl) error {
@@ -353,8 +401,23 @@ func makeTableASTMap(filename string, src interface{}) (map[string]*TableAST, er
 	}
 	ast.FileExports(f)
 	tableASTMap := map[string]*TableAST{}
+	types := map[string]string{}
+	stringTypes := map[string]bool{}
+	constValues := map[string][]string{}
 	ast.Inspect(f, func(n ast.Node) bool {
 		switch x := n.(type) {
+		case *ast.GenDecl:
+			if x.Tok == token.CONST {
+				collectConstValues(x, constValues)
+				return false
+			}
+			// the doc comment of `type T struct' is attached to the declaration.
+			if len(x.Specs) == 1 {
+				if spec, ok := x.Specs[0].(*ast.TypeSpec); ok && spec.Doc == nil {
+					spec.Doc = x.Doc
+				}
+			}
+			return true
 		case *ast.TypeSpec:
 			if t, ok := x.Type.(*ast.StructType); ok {
 				tableName := x.Name.Name
@@ -371,16 +434,83 @@ func makeTableASTMap(filename string, src interface{}) (map[string]*TableAST, er
 					tableASTMap[schemaTableName].IndexSchema = t
 				} else {
 					tableASTMap[schemaTableName].Schema = t
+					tableASTMap[schemaTableName].Doc = x.Doc
+				}
+			}
+			if _, ok := x.Type.(*ast.StructType); !ok {
+				// the types that can't be the field type are ignored.
+				if name, err := detectTypeName(x.Type); err == nil {
+					types[x.Name.Name] = name
 				}
 			}
+			if ident, ok := x.Type.(*ast.Ident); ok && ident.Name == "string" && !x.Assign.IsValid() {
+				stringTypes[x.Name.Name] = true
+			}
 			return false
 		default:
 			return true
 		}
 	})
+	enums := map[string][]string{}
+	for name, values := range constValues {
+		if stringTypes[name] {
+			enums[name] = values
+		}
+	}
+	for _, t := range tableASTMap {
+		t.types = types
+		t.enums = enums
+	}
 	return tableASTMap, nil
 }
 
+// collectConstValues adds the values of the string constants that have the
+// named type in the declaration to values by the name of the type, such as
+//
+//	const (
+//		StatusActive Status = "active"
+//		StatusBanned Status = "banned"
+//	)
+func collectConstValues(decl *ast.GenDecl, values map[string][]string) {
+	for _, spec := range decl.Specs {
+		vspec, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		ident, ok := vspec.Type.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		for _, v := range vspec.Values {
+			lit, ok := v.(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				continue
+			}
+			if s, err := strconv.Unquote(lit.Value); err == nil {
+				values[ident.Name] = append(values[ident.Name], s)
+			}
+		}
+	}
+}
+
+// parseSchema returns the schema from Go's structs.
+// It has only the tables managed by Migu.
+func parseSchema(filename string, src interface{}, o *option) (*schema.Schema, error) {
+	tableASTMap, err := makeTableASTMap(filename, src)
+	if err != nil {
+		return nil, err
+	}
+	s := &schema.Schema{}
+	for _, name := range sortTableASTNames(tableASTMap) {
+		table, err := tableASTMap[name].Table()
+		if err != nil {
+			return nil, err
+		}
+		s.Tables = append(s.Tables, table)
+	}
+	return o.filterTables(s)
+}
+
 func sortTableASTNames(tableASTMap map[string]*TableAST) []string {
 	names := make([]string, 0, len(tableASTMap))
 	for name := range tableASTMap {
@@ -408,42 +538,65 @@ func detectTypeName(n ast.Node) (string, error) {
 			return "", err
 		}
 		return "*" + name, nil
+	case *ast.IndexExpr:
+		// the generic type such as sql.Null[int64].
+		name, err := detectTypeName(t.X)
+		if err != nil {
+			return "", err
+		}
+		param, err := detectTypeName(t.Index)
+		if err != nil {
+			return "", err
+		}
+		return name + "[" + param + "]", nil
+	case *ast.ArrayType:
+		name, err := detectTypeName(t.Elt)
+		if err != nil {
+			return "", err
+		}
+		if lit, ok := t.Len.(*ast.BasicLit); ok {
+			return "[" + lit.Value + "]" + name, nil
+		}
+		if t.Len != nil {
+			return "", fmt.Errorf("migu: unsupported length of the array %T", t.Len)
+		}
+		return "[]" + name, nil
 	default:
 		return "", fmt.Errorf("migu: BUG: unknown type %T", t)
 	}
 }
 
-func columnSQL(d dialect.Dialect, f *field) string {
-	colType, null := d.ColumnType(f.Type, f.Size, f.AutoIncrement)
-	column := []string{d.Quote(toSchemaFieldName(f.Name)), colType}
-	if !null {
-		column = append(column, "NOT NULL")
-	}
-	if f.Default != "" {
-		column = append(column, "DEFAULT", formatDefault(d, f.Type, f.Default))
-	}
-	if f.PrimaryKey {
-		column = append(column, "PRIMARY KEY")
-	}
-	if f.AutoIncrement && d.AutoIncrement() != "" {
-		column = append(column, d.AutoIncrement())
+// importPackages returns the packages of the field types of the schema.
+// importPackages returns the packages of the Go's types of the columns.
+func importPackages(s *schema.Schema, nullTypes bool) ([]string, error) {
+	var hasSQL, hasJSON, hasTime bool
+	for _, table := range s.Tables {
+		for _, column := range table.Columns {
+			typ, err := goFieldType(column, nullTypes)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case strings.Contains(typ, "sql."):
+				hasSQL = true
+			case strings.Contains(typ, "json."):
+				hasJSON = true
+			case strings.Contains(typ, "time."):
+				hasTime = true
+			}
+		}
 	}
-	if f.Unique {
-		column = append(column, "UNIQUE")
+	var pkgs []string
+	if hasSQL {
+		pkgs = append(pkgs, "database/sql")
 	}
-	if f.Comment != "" {
-		column = append(column, "COMMENT", d.QuoteString(f.Comment))
+	if hasJSON {
+		pkgs = append(pkgs, "encoding/json")
 	}
-	return strings.Join(column, " ")
-}
-
-func hasDatetimeColumn(tables map[string]*Table) bool {
-	for _, table := range tables {
-		if table.HasDatetimeColumn() {
-			return true
-		}
+	if hasTime {
+		pkgs = append(pkgs, "time")
 	}
-	return false
+	return pkgs, nil
 }
 
 func importAST(pkg string) ast.Decl {
@@ -460,10 +613,16 @@ func importAST(pkg string) ast.Decl {
 	}
 }
 
-func structAST(name string, table *Table) (ast.Decl, error) {
+func structAST(table *schema.Table, nullTypes bool) (ast.Decl, error) {
 	var fields []*ast.Field
-	for _, schema := range table.Columns {
-		f, err := schema.fieldAST()
+	for _, column := range table.Columns {
+		var fk *schema.ForeignKey
+		for _, k := range table.ForeignKeys {
+			if len(k.Columns) == 1 && k.Columns[0] == column.Name {
+				fk = k
+			}
+		}
+		f, err := fieldAST(column, fk, enumTypeName(table, column), nullTypes)
 		if err != nil {
 			return nil, err
 		}
@@ -473,7 +632,7 @@ func structAST(name string, table *Table) (ast.Decl, error) {
 		Tok: token.TYPE,
 		Specs: []ast.Spec{
 			&ast.TypeSpec{
-				Name: ast.NewIdent(toPublicStructName(name)),
+				Name: ast.NewIdent(toPublicStructName(table.Name)),
 				Type: &ast.StructType{
 					Fields: &ast.FieldList{
 						List: fields,
@@ -484,19 +643,66 @@ func structAST(name string, table *Table) (ast.Decl, error) {
 	}, nil
 }
 
-func indexStructAST(tableName string, indexes []*Index) (ast.Decl, error) {
-	names := make([]string, 0, len(indexes))
-	indexMap := make(map[string]*Index)
-	for _, index := range indexes {
-		names = append(names, index.Name)
-		indexMap[index.Name] = index
+// enumTypeName returns the name of the named type for the values of ENUM or
+// SET column.
+func enumTypeName(table *schema.Table, column *schema.Column) string {
+	return toPublicStructName(table.Name) + toStructPublicFieldName(column.Name)
+}
+
+// enumDecls returns the named types and the constants of the values of ENUM
+// and SET columns of the table. The name of the constant is the name of the
+// type followed by the value, or its index if the value can't be a name.
+func enumDecls(table *schema.Table) []ast.Decl {
+	var decls []ast.Decl
+	for _, column := range table.Columns {
+		if !column.IsEnum() {
+			continue
+		}
+		typeName := enumTypeName(table, column)
+		decls = append(decls, &ast.GenDecl{
+			Tok: token.TYPE,
+			Specs: []ast.Spec{
+				&ast.TypeSpec{
+					Name: ast.NewIdent(typeName),
+					Type: ast.NewIdent("string"),
+				},
+			},
+		})
+		var specs []ast.Spec
+		names := map[string]bool{}
+		for i, value := range column.Values {
+			name := typeName + toConstNameSuffix(value)
+			for n := i; name == typeName || names[name]; n++ {
+				name = typeName + strconv.Itoa(n)
+			}
+			names[name] = true
+			specs = append(specs, &ast.ValueSpec{
+				Names: []*ast.Ident{ast.NewIdent(name)},
+				Type:  ast.NewIdent(typeName),
+				Values: []ast.Expr{
+					&ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(value)},
+				},
+			})
+		}
+		decls = append(decls, &ast.GenDecl{
+			Tok:    token.CONST,
+			Lparen: 1,
+			Specs:  specs,
+		})
 	}
-	sort.Strings(names)
+	return decls
+}
+
+func indexStructAST(table *schema.Table) (ast.Decl, error) {
+	indexes := make([]*schema.Index, len(table.Indexes))
+	copy(indexes, table.Indexes)
+	sort.Slice(indexes, func(i, j int) bool {
+		return indexes[i].Name < indexes[j].Name
+	})
 
 	var fields []*ast.Field
-	for i, name := range names {
-		index := indexMap[name]
-		f, err := index.AsASTField(i)
+	for i, index := range indexes {
+		f, err := indexASTField(index, i)
 		if err != nil {
 			return nil, fmt.Errorf("migu: indexStructAST: " + err.Error())
 		}
@@ -506,7 +712,7 @@ func indexStructAST(tableName string, indexes []*Index) (ast.Decl, error) {
 		Tok: token.TYPE,
 		Specs: []ast.Spec{
 			&ast.TypeSpec{
-				Name: ast.NewIdent(toPublicStructName(tableName) + "Index"),
+				Name: ast.NewIdent(toPublicStructName(table.Name) + "Index"),
 				Type: &ast.StructType{
 					Fields: &ast.FieldList{
 						List: fields,
@@ -527,12 +733,13 @@ func parseStructTag(f *field, tag reflect.StructTag) error {
 		switch optval[0] {
 		case tagDefault:
 			if len(optval) > 1 {
-				if f.Type == "bool" {
-					f.Default = normalizeBoolDefaultTagTo0or1(optval[1])
-				} else {
-					f.Default = optval[1]
-				}
+				f.Default, f.DefaultExpr = parseDefaultTag(optval[1], isStringType(f.Type))
 			}
+		case tagDefaultExpr:
+			if len(optval) < 2 {
+				return fmt.Errorf("`%s' tag must specify the parameter", tagDefaultExpr)
+			}
+			f.Default, f.DefaultExpr = schema.NormalizeDefaultExpr(optval[1]), true
 		case tagPrimaryKey:
 			f.PrimaryKey = true
 		case tagAutoIncrement:
@@ -550,6 +757,77 @@ func parseStructTag(f *field, tag reflect.StructTag) error {
 				return err
 			}
 			f.Size = size
+		case tagPrecision, tagScale:
+			if len(optval) < 2 {
+				return fmt.Errorf("`%s' tag must specify the parameter", optval[0])
+			}
+			n, err := strconv.ParseUint(optval[1], 10, 64)
+			if err != nil {
+				return err
+			}
+			if optval[0] == tagPrecision {
+				f.Precision = n
+			} else {
+				f.Scale = n
+			}
+		case tagType:
+			if len(optval) < 2 || strings.TrimSpace(optval[1]) == "" {
+				return fmt.Errorf("`%s' tag must specify the parameter", tagType)
+			}
+			f.SQLType = optval[1]
+		case tagEnum, tagSet:
+			// the values can be omitted for the type that has the constants.
+			f.EnumType = strings.ToUpper(optval[0])
+			if len(optval) > 1 && optval[1] != "" {
+				f.Values = strings.Split(optval[1], ",")
+			}
+		case tagCharset, tagCollate:
+			if len(optval) < 2 || optval[1] == "" {
+				return fmt.Errorf("`%s' tag must specify the parameter", optval[0])
+			}
+			if optval[0] == tagCharset {
+				f.Charset = optval[1]
+			} else {
+				f.Collation = optval[1]
+			}
+		case tagGenerated:
+			if len(optval) < 2 || schema.TrimExpr(optval[1]) == "" {
+				return fmt.Errorf("`%s' tag must specify the parameter", tagGenerated)
+			}
+			f.Generated = schema.TrimExpr(optval[1])
+		case tagStored:
+			f.Stored = true
+		case tagDate, tagTime, tagTimestamp, tagYear:
+			f.TimeType = strings.ToUpper(optval[0])
+		case tagRename:
+			if len(optval) < 2 || optval[1] == "" {
+				return fmt.Errorf("`%s' tag must specify the parameter", tagRename)
+			}
+			f.RenamedFrom = optval[1]
+		case tagForeignKey:
+			var ref []string
+			if len(optval) > 1 {
+				ref = strings.SplitN(optval[1], ".", 2)
+			}
+			if len(ref) < 2 || ref[0] == "" || ref[1] == "" {
+				return fmt.Errorf("`%s' tag must specify the parameter in the form of table.column", tagForeignKey)
+			}
+			f.ReferencedTable, f.ReferencedColumn = ref[0], ref[1]
+		case tagOnUpdate:
+			// ON UPDATE of the column or the foreign key.
+			if len(optval) > 1 && schema.IsCurrentTimestamp(optval[1]) {
+				f.UpdateExpr = schema.NormalizeDefaultExpr(optval[1])
+				continue
+			}
+			if len(optval) < 2 || !isForeignKeyAction(optval[1]) {
+				return fmt.Errorf("`%s' tag must specify CURRENT_TIMESTAMP, or one of CASCADE, SET NULL, SET DEFAULT, RESTRICT and NO ACTION", optval[0])
+			}
+			f.OnUpdate = strings.ToUpper(optval[1])
+		case tagOnDelete:
+			if len(optval) < 2 || !isForeignKeyAction(optval[1]) {
+				return fmt.Errorf("`%s' tag must specify one of CASCADE, SET NULL, SET DEFAULT, RESTRICT and NO ACTION", optval[0])
+			}
+			f.OnDelete = strings.ToUpper(optval[1])
 		default:
 			return fmt.Errorf("unknown option: `%s'", opt)
 		}
@@ -557,6 +835,33 @@ func parseStructTag(f *field, tag reflect.StructTag) error {
 	return nil
 }
 
+// parseDefaultTag returns the default value of the `default' tag and whether
+// it's an expression. The value of the string field is always the string.
+// For the other fields, NULL, CURRENT_TIMESTAMP and its synonyms, and the
+// value enclosed in parentheses such as (UUID()) are the expressions, and the
+// value enclosed in single quotes is the value as is, such as '0'.
+func parseDefaultTag(s string, str bool) (string, bool) {
+	switch {
+	case str:
+		return s, false
+	case len(s) > 1 && strings.HasPrefix(s, "'") && strings.HasSuffix(s, "'"):
+		return strings.Replace(s[1:len(s)-1], "''", "'", -1), false
+	case strings.EqualFold(s, "NULL"):
+		return "NULL", true
+	case schema.IsCurrentTimestamp(s), strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")"):
+		return schema.NormalizeDefaultExpr(s), true
+	}
+	return s, false
+}
+
+func isForeignKeyAction(s string) bool {
+	switch strings.ToUpper(s) {
+	case "CASCADE", "SET NULL", "SET DEFAULT", "RESTRICT", "NO ACTION":
+		return true
+	}
+	return false
+}
+
 func normalizeBoolDefaultTagTo0or1(s string) string {
 	switch strings.ToLower(s) {
 	case "1", "true", "on":
@@ -565,52 +870,18 @@ func normalizeBoolDefaultTagTo0or1(s string) string {
 	return "0"
 }
 
-func isSizeRequiredType(typeName string) bool {
-	types := []string{"string", "*string", "sql.NullString"}
+func isBinaryType(typeName string) bool {
+	types := []string{"[]byte", "*[]byte", "[]uint8", "*[]uint8", "sql.Null[[]byte]", "sql.Null[[]uint8]"}
 	return inStrings(types, typeName)
 }
 
-func parseIndexStructTag(tag reflect.StructTag) (*Index, error) {
-	migu := tag.Get("migu")
-	if migu == "" {
-		return nil, fmt.Errorf("migu: parseIndexStructTag: index tag must not be empty")
-	}
-	index := &Index{}
-	isPrimaryKey := false
-	for _, opt := range strings.Split(migu, tagSeparater) {
-		optval := strings.SplitN(opt, ":", 2)
-		if len(optval) < 1 {
-			return nil, fmt.Errorf("migu: parseIndexStructTag: 'migu' tag must specify values")
-		}
-		switch optval[0] {
-		case tagPrimaryKey:
-			isPrimaryKey = true
-		case tagIndex:
-			if len(optval) < 2 {
-				return nil, fmt.Errorf("migu: parseIndexStructTag: '%s' tag must specify parameters", tagIndex)
-			}
-			params := strings.SplitN(optval[1], ",", -1)
-			if len(params) < 1 {
-				return nil, fmt.Errorf("migu: parseIndexStructTag: '%s' tag must specify one column at least", tagIndex)
-			}
-			if len(params) == 1 {
-				index.Name = params[0]
-				index.ColumnNames = params
-			} else {
-				index.Name = params[0]
-				index.ColumnNames = params[1:]
-			}
-		case tagUnique:
-			index.Unique = true
-		default:
-			return nil, fmt.Errorf("migu: parseIndexStructTag: unknown option: `%s'", opt)
-		}
-	}
-	if isPrimaryKey {
-		index.Name = "PRIMARY"
-		index.Unique = true
-	}
-	return index, nil
+func isSizeRequiredType(typeName string) bool {
+	return isStringType(typeName)
+}
+
+func isStringType(typeName string) bool {
+	types := []string{"string", "*string", "sql.NullString", "sql.Null[string]"}
+	return inStrings(types, typeName)
 }
 
 func toPublicStructName(s string) string {
@@ -621,6 +892,20 @@ func toStructPublicFieldName(s string) string {
 	return stringutil.ToUpperCamelCase(s)
 }
 
+// toConstNameSuffix returns the value in upper camel case without the
+// characters that can't be a part of the name, such as "in-progress" to
+// "InProgress".
+func toConstNameSuffix(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		r, n := utf8.DecodeRuneInString(word)
+		words[i] = string(unicode.ToUpper(r)) + word[n:]
+	}
+	return strings.Join(words, "")
+}
+
 func toSchemaTableName(s string) string {
 	return snakecase.SnakeCase(s)
 }
@@ -3,12 +3,17 @@ package migu_test
 import (
 	"database/sql"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"os"
 	"reflect"
 	"sort"
 	"testing"
 
 	"github.com/astronoka/migu"
+	"github.com/astronoka/migu/dialect"
+	"github.com/astronoka/migu/schema"
 	_ "github.com/go-sql-driver/mysql"
 )
 
@@ -56,6 +61,10 @@ func TestDiffWithSrc(t *testing.T) {
 		"*uint32":         "INT UNSIGNED",
 		"*uint64":         "BIGINT UNSIGNED",
 		"sql.NullInt64":   "BIGINT",
+		"sql.NullInt32":   "INT",
+		"sql.NullInt16":   "SMALLINT",
+		"sql.NullByte":    "TINYINT UNSIGNED",
+		"sql.Null[int64]": "BIGINT",
 		"string":          "VARCHAR(255) NOT NULL",
 		"*string":         "VARCHAR(255)",
 		"sql.NullString":  "VARCHAR(255)",
@@ -69,6 +78,7 @@ func TestDiffWithSrc(t *testing.T) {
 		"sql.NullFloat64": "DOUBLE",
 		"time.Time":       "DATETIME NOT NULL",
 		"*time.Time":      "DATETIME",
+		"sql.NullTime":    "DATETIME",
 	}
 	for t1, s1 := range types {
 		for t2, s2 := range types {
@@ -257,3 +267,53 @@ func testDiffWithSrc(t *testing.T, t1, s1, t2, s2 string) error {
 //		}()
 //	}
 //}
+
+func TestTableASTDeprecated(t *testing.T) {
+	f, err := parser.ParseFile(token.NewFileSet(), "", `package migu_test
+
+type User struct {
+	ID   int64 `+"`migu:\"pk\"`"+`
+	Name string
+}`, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := f.Decls[0].(*ast.GenDecl).Specs[0].(*ast.TypeSpec)
+	table := &migu.TableAST{Name: spec.Name.Name, Schema: spec.Type.(*ast.StructType)}
+	d := &dialect.MySQL{}
+	for i, v := range []struct {
+		name   string
+		fn     func() ([]string, error)
+		expect []string
+	}{
+		{"CreateTableQuery", func() ([]string, error) {
+			return table.CreateTableQuery(d)
+		}, []string{"CREATE TABLE `user` (\n" +
+			"  `id` BIGINT NOT NULL PRIMARY KEY, `name` VARCHAR(255) NOT NULL\n" +
+			")"}},
+		{"AlterTableQueries", func() ([]string, error) {
+			return table.AlterTableQueries(d, &migu.Table{
+				Columns: []*schema.Column{{Name: "id", Type: "BIGINT"}},
+				Indexes: []*migu.Index{{Name: "PRIMARY", Unique: true, ColumnNames: []string{"id"}}},
+			})
+		}, []string{"ALTER TABLE `user` ADD `name` VARCHAR(255) NOT NULL AFTER `id`"}},
+		{"GenerateDropFieldSQLs", func() ([]string, error) {
+			return table.GenerateDropFieldSQLs(d, map[string]*schema.Column{
+				"id":  {Name: "id", Type: "BIGINT"},
+				"age": {Name: "age", Type: "INT"},
+			})
+		}, []string{"ALTER TABLE `user` DROP `age`"}},
+		{"GenerateAddIndexSQLs", func() ([]string, error) {
+			return table.GenerateAddIndexSQLs(d, map[string]*migu.Index{})
+		}, []string{"ALTER TABLE `user` ADD PRIMARY KEY (`id`)"}},
+	} {
+		actual, err := v.fn()
+		if err != nil {
+			t.Errorf("%d: TableAST.%s => %#v", i, v.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(actual, v.expect) {
+			t.Errorf("%d: TableAST.%s => %#v; want %#v", i, v.name, actual, v.expect)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package migu
+
+import "github.com/astronoka/migu/dialect"
+
+// Option is an option for Sync, Diff and Fprint.
+type Option func(*option)
+
+type option struct {
+	dialect       dialect.Dialect
+	snapshot      string
+	guard         bool
+	allowedTables []string
+	includes      []string
+	excludes      []string
+	nullTypes     bool
+}
+
+func newOption(opts []Option) *option {
+	o := &option{
+		dialect: &dialect.MySQL{},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithDialect specifies the SQL dialect of the database.
+// If it isn't specified, MySQL is used.
+func WithDialect(d dialect.Dialect) Option {
+	return func(o *option) {
+		o.dialect = d
+	}
+}
+
+// WithSnapshot specifies the file of the DDL that is used as the current
+// schema instead of the database, such as the output of
+// `mysqldump --no-data'. The db parameter of Diff and Fprint may be nil.
+// The dialect must implement dialect.DDLParser.
+func WithSnapshot(filename string) Option {
+	return func(o *option) {
+		o.snapshot = filename
+	}
+}
+
+// WithGuard makes Sync and Diff refuse the plan that has the lossy or
+// destructive operations, and return *UnsafeError.
+// The operations on allowedTables are allowed.
+func WithGuard(allowedTables ...string) Option {
+	return func(o *option) {
+		o.guard = true
+		o.allowedTables = allowedTables
+	}
+}
+
+// WithInclude limits the tables that are managed by Migu to the ones that
+// match any of the patterns. The other tables are never altered, dropped or
+// dumped.
+// The pattern is a glob pattern (e.g. user_*), or a regular expression
+// enclosed in slashes (e.g. /^user_[0-9]+$/).
+func WithInclude(patterns ...string) Option {
+	return func(o *option) {
+		o.includes = append(o.includes, patterns...)
+	}
+}
+
+// WithExclude excludes the tables that match any of the patterns from the
+// tables that are managed by Migu.
+// The pattern is the same as WithInclude.
+func WithExclude(patterns ...string) Option {
+	return func(o *option) {
+		o.excludes = append(o.excludes, patterns...)
+	}
+}
+
+// WithNullTypes makes Fprint print the Null types of database/sql such as
+// sql.NullInt64 and sql.NullTime for the nullable columns instead of the
+// pointer types. The columns that don't have the Null type are the pointer
+// types.
+func WithNullTypes() Option {
+	return func(o *option) {
+		o.nullTypes = true
+	}
+}
@@ -0,0 +1,280 @@
+package migu
+
+import (
+	"sort"
+
+	"github.com/astronoka/migu/dialect"
+	"github.com/astronoka/migu/schema"
+)
+
+// OpType is the type of the operation of the migration.
+type OpType string
+
+const (
+	OpCreateTable  OpType = "CreateTable"
+	OpDropTable    OpType = "DropTable"
+	OpAddColumn    OpType = "AddColumn"
+	OpDropColumn   OpType = "DropColumn"
+	OpModifyColumn OpType = "ModifyColumn"
+	OpAddIndex     OpType = "AddIndex"
+	OpDropIndex    OpType = "DropIndex"
+	OpRenameTable  OpType = "RenameTable"
+	OpRenameColumn OpType = "RenameColumn"
+
+	OpAddForeignKey  OpType = "AddForeignKey"
+	OpDropForeignKey OpType = "DropForeignKey"
+
+	// OpRebuildTable applies all the changes of the table at once by
+	// rebuilding the table. It's used instead of the other operations on the
+	// table if the dialect can't alter the table in place (e.g. SQLite3).
+	OpRebuildTable OpType = "RebuildTable"
+)
+
+// Op is an operation of the migration.
+type Op struct {
+	Type OpType
+
+	// Table is the name of the table.
+	Table string
+
+	// The definitions before and after the operation. Only the ones for the
+	// type of the operation are set. The current one is nil if it's added,
+	// and the expected one is nil if it's dropped.
+	CurrentTable   *schema.Table
+	ExpectedTable  *schema.Table
+	CurrentColumn  *schema.Column
+	ExpectedColumn *schema.Column
+	CurrentIndex   *schema.Index
+	ExpectedIndex  *schema.Index
+
+	CurrentForeignKey  *schema.ForeignKey
+	ExpectedForeignKey *schema.ForeignKey
+
+	// SQL is the statements of the operation in the dialect.
+	SQL []string
+
+	// Safety is the risk of the operation to lose the data.
+	Safety Safety
+}
+
+// Queries returns the SQL statements of the operations in order.
+func Queries(ops []*Op) []string {
+	var queries []string
+	for _, op := range ops {
+		queries = append(queries, op.SQL...)
+	}
+	return queries
+}
+
+// PlanSchema returns the operations to migrate the current schema to the
+// expected schema. It compares the schemas without accessing the database.
+// The operations are ordered canonically regardless of the order of the
+// tables and the indexes in the schemas. The referenced tables are created
+// before and dropped after the tables that have the foreign keys.
+func PlanSchema(d dialect.Dialect, current, expected *schema.Schema) []*Op {
+	current, expected = canonicalSchema(current), canonicalSchema(expected)
+	var ops []*Op
+	renamed := map[string]bool{}
+	for _, table := range sortTablesByDependency(expected.Tables) {
+		currentTable := current.Table(table.Name)
+		if currentTable == nil && table.RenamedFrom != "" && expected.Table(table.RenamedFrom) == nil {
+			if old := current.Table(table.RenamedFrom); old != nil {
+				ops = append(ops, &Op{
+					Type:          OpRenameTable,
+					Table:         table.Name,
+					CurrentTable:  old,
+					ExpectedTable: table,
+					SQL:           d.RenameTable(old, table.Name),
+				})
+				renamed[old.Name] = true
+				t := *old
+				t.Name = table.Name
+				currentTable = &t
+			}
+		}
+		if table.Option.Charset == "" {
+			// the type of the column may depend on the character set, such
+			// as the maximum length of VARCHAR in MySQL, so the table that
+			// doesn't have it is compared and created in the current one.
+			table.Option.Charset = current.Charset
+			if currentTable != nil {
+				table.Option.Charset = currentTable.Option.Charset
+			}
+		}
+		if currentTable == nil {
+			ops = append(ops, &Op{
+				Type:          OpCreateTable,
+				Table:         table.Name,
+				ExpectedTable: table,
+				SQL:           d.CreateTable(table),
+			})
+			continue
+		}
+		if diff := diffTable(d, currentTable, table); !diff.IsEmpty() {
+			ops = append(ops, planAlterTable(d, diff)...)
+		}
+	}
+	// the tables are dropped in the reverse order of the dependency, and in
+	// the order of the name as far as possible.
+	currentTables := make([]*schema.Table, len(current.Tables))
+	for i, table := range current.Tables {
+		currentTables[len(currentTables)-1-i] = table
+	}
+	currentTables = sortTablesByDependency(currentTables)
+	for i := len(currentTables) - 1; i >= 0; i-- {
+		table := currentTables[i]
+		if !renamed[table.Name] && expected.Table(table.Name) == nil {
+			ops = append(ops, &Op{
+				Type:         OpDropTable,
+				Table:        table.Name,
+				CurrentTable: table,
+				SQL:          d.DropTable(table),
+				Safety:       Destructive,
+			})
+		}
+	}
+	return ops
+}
+
+// planAlterTable splits the changes of the table into the operations.
+// The SQL of each operation is built from the diff that has only the change
+// of the operation.
+func planAlterTable(d dialect.Dialect, diff *schema.TableDiff) []*Op {
+	if r, ok := d.(dialect.Rebuilder); ok && r.NeedsRebuild(diff) {
+		safety := Safe
+		for _, m := range diff.ModifyColumns {
+			if modifySafety(d, diff.Current, m.Current, m.Expected) == Lossy {
+				safety = Lossy
+			}
+		}
+		if len(diff.DropColumns) > 0 {
+			safety = Destructive
+		}
+		return []*Op{{
+			Type:          OpRebuildTable,
+			Table:         diff.Expected.Name,
+			CurrentTable:  diff.Current,
+			ExpectedTable: diff.Expected,
+			SQL:           d.AlterTable(diff),
+			Safety:        safety,
+		}}
+	}
+	var ops []*Op
+	add := func(op *Op, change *schema.TableDiff) {
+		change.Current, change.Expected = diff.Current, diff.Expected
+		op.Table = diff.Expected.Name
+		op.SQL = d.AlterTable(change)
+		if len(op.SQL) > 0 {
+			ops = append(ops, op)
+		}
+	}
+	for _, r := range diff.RenameColumns {
+		add(&Op{
+			Type:           OpRenameColumn,
+			CurrentColumn:  r.Current,
+			ExpectedColumn: r.Expected,
+		}, &schema.TableDiff{RenameColumns: []*schema.ColumnDiff{r}})
+	}
+	for _, fk := range diff.DropForeignKeys {
+		add(&Op{
+			Type:              OpDropForeignKey,
+			CurrentForeignKey: fk,
+		}, &schema.TableDiff{DropForeignKeys: []*schema.ForeignKey{fk}})
+	}
+	for _, index := range diff.DropIndexes {
+		add(&Op{
+			Type:         OpDropIndex,
+			CurrentIndex: index,
+		}, &schema.TableDiff{DropIndexes: []*schema.Index{index}})
+	}
+	for _, c := range diff.DropColumns {
+		add(&Op{
+			Type:          OpDropColumn,
+			CurrentColumn: c,
+			Safety:        Destructive,
+		}, &schema.TableDiff{DropColumns: []*schema.Column{c}})
+	}
+	for _, c := range diff.AddColumns {
+		add(&Op{
+			Type:           OpAddColumn,
+			ExpectedColumn: c,
+		}, &schema.TableDiff{AddColumns: []*schema.Column{c}})
+	}
+	for _, m := range diff.ModifyColumns {
+		add(&Op{
+			Type:           OpModifyColumn,
+			CurrentColumn:  m.Current,
+			ExpectedColumn: m.Expected,
+			Safety:         modifySafety(d, diff.Current, m.Current, m.Expected),
+		}, &schema.TableDiff{ModifyColumns: []*schema.ColumnDiff{m}})
+	}
+	for _, index := range diff.AddIndexes {
+		add(&Op{
+			Type:          OpAddIndex,
+			ExpectedIndex: index,
+		}, &schema.TableDiff{AddIndexes: []*schema.Index{index}})
+	}
+	for _, fk := range diff.AddForeignKeys {
+		add(&Op{
+			Type:               OpAddForeignKey,
+			ExpectedForeignKey: fk,
+		}, &schema.TableDiff{AddForeignKeys: []*schema.ForeignKey{fk}})
+	}
+	return ops
+}
+
+// canonicalSchema returns the copy of the schema that the tables are ordered
+// by the name, and the indexes and the foreign keys of each table are ordered
+// by the name with the primary key first. The order of the columns is kept
+// because it's a part of the schema.
+func canonicalSchema(s *schema.Schema) *schema.Schema {
+	tables := make([]*schema.Table, len(s.Tables))
+	for i, table := range s.Tables {
+		t := *table
+		t.Indexes = append([]*schema.Index(nil), table.Indexes...)
+		sort.SliceStable(t.Indexes, func(i, j int) bool {
+			if pi, pj := t.Indexes[i].IsPrimaryKey(), t.Indexes[j].IsPrimaryKey(); pi != pj {
+				return pi
+			}
+			return t.Indexes[i].Name < t.Indexes[j].Name
+		})
+		t.ForeignKeys = append([]*schema.ForeignKey(nil), table.ForeignKeys...)
+		sort.SliceStable(t.ForeignKeys, func(i, j int) bool {
+			return t.ForeignKeys[i].Name < t.ForeignKeys[j].Name
+		})
+		tables[i] = &t
+	}
+	sort.SliceStable(tables, func(i, j int) bool {
+		return tables[i].Name < tables[j].Name
+	})
+	return &schema.Schema{Tables: tables, Charset: s.Charset}
+}
+
+// sortTablesByDependency returns the tables ordered so that the referenced
+// tables by the foreign keys come first. The order of the tables is kept as
+// far as possible. The circular references are ignored.
+func sortTablesByDependency(tables []*schema.Table) []*schema.Table {
+	tableMap := make(map[string]*schema.Table, len(tables))
+	for _, table := range tables {
+		tableMap[table.Name] = table
+	}
+	sorted := make([]*schema.Table, 0, len(tables))
+	visited := make(map[string]bool, len(tables))
+	var visit func(table *schema.Table)
+	visit = func(table *schema.Table) {
+		if visited[table.Name] {
+			return
+		}
+		visited[table.Name] = true
+		for _, fk := range table.ForeignKeys {
+			if ref, exist := tableMap[fk.ReferencedTable]; exist {
+				visit(ref)
+			}
+		}
+		sorted = append(sorted, table)
+	}
+	for _, table := range tables {
+		visit(table)
+	}
+	return sorted
+}
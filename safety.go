@@ -0,0 +1,187 @@
+package migu
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/astronoka/migu/dialect"
+	"github.com/astronoka/migu/schema"
+)
+
+// Safety is the risk of the operation to lose the data.
+type Safety int
+
+const (
+	// Safe is the operation that doesn't lose the data.
+	Safe Safety = iota
+
+	// Lossy is the operation that may lose a part of the data, such as
+	// narrowing the type or the size of the column, making the column NOT
+	// NULL, or dropping the default of the NOT NULL column.
+	Lossy
+
+	// Destructive is the operation that drops the table or the column.
+	Destructive
+)
+
+func (s Safety) String() string {
+	switch s {
+	case Safe:
+		return "safe"
+	case Lossy:
+		return "lossy"
+	case Destructive:
+		return "destructive"
+	default:
+		return fmt.Sprintf("Safety(%d)", int(s))
+	}
+}
+
+// UnsafeError is returned by Sync and Diff when the plan has the lossy or
+// destructive operations that aren't allowed by WithGuard.
+type UnsafeError struct {
+	Ops []*Op
+}
+
+func (e *UnsafeError) Error() string {
+	lines := []string{fmt.Sprintf("migu: refused %d lossy or destructive operation(s):", len(e.Ops))}
+	for _, op := range e.Ops {
+		target := op.Table
+		if c := op.column(); c != nil {
+			target += "." + c.Name
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %s %s", op.Safety, op.Type, target))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// check returns *UnsafeError if ops have the operations that aren't allowed.
+func (o *option) check(ops []*Op) error {
+	if !o.guard {
+		return nil
+	}
+	allowed := make(map[string]bool, len(o.allowedTables))
+	for _, name := range o.allowedTables {
+		allowed[name] = true
+	}
+	var unsafe []*Op
+	for _, op := range ops {
+		if op.Safety != Safe && !allowed[op.Table] {
+			unsafe = append(unsafe, op)
+		}
+	}
+	if len(unsafe) > 0 {
+		return &UnsafeError{Ops: unsafe}
+	}
+	return nil
+}
+
+func (op *Op) column() *schema.Column {
+	if op.ExpectedColumn != nil {
+		return op.ExpectedColumn
+	}
+	return op.CurrentColumn
+}
+
+var integerRanks = map[string]int{
+	"BOOL":      1,
+	"TINYINT":   2,
+	"SMALLINT":  3,
+	"MEDIUMINT": 4,
+	"INT":       5,
+	"BIGINT":    6,
+}
+
+// modifySafety returns the safety of the modification of the column in the
+// table. Changing the character set is Lossy because the characters that the
+// new one doesn't have are lost. Making the column generated is Lossy as
+// well because its values are replaced.
+func modifySafety(d dialect.Dialect, table *schema.Table, current, expected *schema.Column) Safety {
+	switch {
+	case current.Generated == "" && expected.Generated != "":
+		// the values are replaced with the generated ones.
+		return Lossy
+	case current.Generated != "" && expected.Generated != "":
+		// the values are generated again.
+		return Safe
+	case current.Generated != "" && !current.Stored:
+		// the values of the virtual column aren't stored.
+		return Lossy
+	}
+	if current.Nullable && !expected.Nullable {
+		return Lossy
+	}
+	if collator, ok := d.(dialect.Collator); ok {
+		currentCharset, _ := collator.ColumnCollation(table, current)
+		expectedCharset, _ := collator.ColumnCollation(table, expected)
+		if currentCharset != expectedCharset {
+			return Lossy
+		}
+	}
+	if !expected.Nullable && current.HasDefault() && !expected.HasDefault() {
+		return Lossy
+	}
+	if columnTypeInTable(d, table, current) == columnTypeInTable(d, table, expected) {
+		return Safe
+	}
+	return typeSafety(current, expected)
+}
+
+// typeSafety returns Lossy if the type of the expected column can't store
+// all the values of the current column.
+func typeSafety(current, expected *schema.Column) Safety {
+	cr, er := integerRanks[current.Type], integerRanks[expected.Type]
+	switch {
+	case cr > 0 && er > 0:
+		if er < cr || (er == cr && current.Unsigned != expected.Unsigned) ||
+			(!current.Unsigned && expected.Unsigned) {
+			return Lossy
+		}
+		return Safe
+	case current.IsString() && expected.IsString(),
+		current.IsBinary() && expected.IsBinary():
+		if columnSize(expected) < columnSize(current) {
+			return Lossy
+		}
+		return Safe
+	case current.Type == "DECIMAL" && expected.Type == "DECIMAL":
+		// both of the integer part and the fractional part must be kept.
+		if expected.Scale < current.Scale || expected.Precision-expected.Scale < current.Precision-current.Scale {
+			return Lossy
+		}
+		return Safe
+	case current.IsTime() && expected.IsTime():
+		// DATE fits in DATETIME and TIMESTAMP, and TIMESTAMP fits in DATETIME.
+		if current.Type != expected.Type &&
+			!(current.Type == "DATE" && (expected.Type == "DATETIME" || expected.Type == "TIMESTAMP")) &&
+			!(current.Type == "TIMESTAMP" && expected.Type == "DATETIME") {
+			return Lossy
+		}
+		if expected.Precision < current.Precision {
+			return Lossy
+		}
+		return Safe
+	case current.IsEnum() && current.Type == expected.Type:
+		// adding or reordering the values keeps the data.
+		for _, v := range current.Values {
+			if !inStrings(expected.Values, v) {
+				return Lossy
+			}
+		}
+		return Safe
+	case current.Type == "FLOAT" && expected.Type == "DOUBLE":
+		return Safe
+	case current.Type == expected.Type:
+		return Safe
+	}
+	return Lossy
+}
+
+// columnSize returns the maximum length of the column. VARBINARY of zero
+// size is BLOB.
+func columnSize(c *schema.Column) uint64 {
+	if c.Type == "VARBINARY" && c.Size == 0 {
+		return 65535
+	}
+	return c.Size
+}
@@ -0,0 +1,37 @@
+package schema
+
+// TableDiff is the difference between the current and the expected table.
+// Each dialect renders it into the statements to alter the table.
+type TableDiff struct {
+	Current       *Table
+	Expected      *Table
+	AddColumns    []*Column
+	DropColumns   []*Column
+	ModifyColumns []*ColumnDiff
+	RenameColumns []*ColumnDiff
+	AddIndexes    []*Index
+	DropIndexes   []*Index
+
+	AddForeignKeys  []*ForeignKey
+	DropForeignKeys []*ForeignKey
+}
+
+// IsEmpty returns whether the tables have no difference.
+func (d *TableDiff) IsEmpty() bool {
+	return len(d.AddColumns) == 0 &&
+		len(d.DropColumns) == 0 &&
+		len(d.ModifyColumns) == 0 &&
+		len(d.RenameColumns) == 0 &&
+		len(d.AddIndexes) == 0 &&
+		len(d.DropIndexes) == 0 &&
+		len(d.AddForeignKeys) == 0 &&
+		len(d.DropForeignKeys) == 0
+}
+
+// ColumnDiff is the difference of the column.
+// In RenameColumns, the names of Current and Expected are the old and the new
+// names. The other changes of the renamed column are in ModifyColumns.
+type ColumnDiff struct {
+	Current  *Column
+	Expected *Column
+}
@@ -0,0 +1,425 @@
+// Package schema provides the dialect-neutral model of the database schema.
+//
+// Both of the Go's struct parser and the database inspectors produce the
+// model, so the schemas can be compared and rendered without a live database.
+package schema
+
+import (
+	"regexp"
+	"strings"
+)
+
+// PrimaryKeyName is the name of the primary key index in all dialects.
+const PrimaryKeyName = "PRIMARY"
+
+// Schema is the set of tables.
+//
+// Charset is the default character set of the database for the tables that
+// don't have the character set. The empty string means the default of the
+// dialect.
+type Schema struct {
+	Tables  []*Table
+	Charset string
+}
+
+// Table returns the table that has the name, or nil if not exists.
+func (s *Schema) Table(name string) *Table {
+	for _, t := range s.Tables {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// Table is the definition of the table.
+//
+// RenamedFrom is the old name of the table to be renamed. It's used only to
+// compare the schemas, and it's ignored if the old table doesn't exist.
+type Table struct {
+	Name        string
+	RenamedFrom string
+	Columns     []*Column
+	Indexes     []*Index
+	ForeignKeys []*ForeignKey
+	Option      TableOption
+}
+
+// Column returns the column that has the name, or nil if not exists.
+func (t *Table) Column(name string) *Column {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// Index returns the index that has the name, or nil if not exists.
+func (t *Table) Index(name string) *Index {
+	for _, index := range t.Indexes {
+		if index.Name == name {
+			return index
+		}
+	}
+	return nil
+}
+
+// ForeignKey returns the foreign key that has the name, or nil if not exists.
+func (t *Table) ForeignKey(name string) *ForeignKey {
+	for _, fk := range t.ForeignKeys {
+		if fk.Name == name {
+			return fk
+		}
+	}
+	return nil
+}
+
+// PrimaryKey returns the primary key of the table, or nil if not exists.
+func (t *Table) PrimaryKey() *Index {
+	return t.Index(PrimaryKeyName)
+}
+
+// TableOption is the options of the table.
+// The empty value means the default of the database.
+type TableOption struct {
+	Engine    string
+	Charset   string
+	Collation string
+	Comment   string
+}
+
+// Column is the definition of the column.
+//
+// Type is the dialect-neutral name of the type such as "INT", "VARCHAR" and
+// "DATETIME". Each dialect renders it into its own type.
+// Size is the length of the character types, or the length in bytes of the
+// binary types. VARBINARY of zero size is BLOB.
+// Precision and Scale are the total number of digits and the number of
+// digits after the decimal point of DECIMAL. Precision is also the fractional
+// seconds precision of DATETIME, TIMESTAMP and TIME.
+// Values is the permitted values of ENUM and SET in order.
+// Charset and Collation are the character set and the collation of the
+// character types. The empty string means the default of the table.
+// Default is the default value without quotes. The empty string means that
+// the column has no default value. If DefaultExpr is true, Default is the
+// expression in the form of NormalizeDefaultExpr such as "CURRENT_TIMESTAMP"
+// and "UUID()", and the expression "NULL" is the same as no default value.
+// OnUpdate is the expression to set on update such as "CURRENT_TIMESTAMP(6)".
+// Generated is the expression of the generated column without the enclosing
+// parentheses, and Stored reports whether its values are stored instead of
+// computed on read.
+// RenamedFrom is the old name of the column to be renamed as well as
+// Table.RenamedFrom.
+type Column struct {
+	Name          string
+	RenamedFrom   string
+	Type          string
+	Size          uint64
+	Precision     uint64
+	Scale         uint64
+	Values        []string
+	Charset       string
+	Collation     string
+	Unsigned      bool
+	Nullable      bool
+	Default       string
+	DefaultExpr   bool
+	OnUpdate      string
+	Generated     string
+	Stored        bool
+	AutoIncrement bool
+	Unique        bool
+	Comment       string
+}
+
+// neutralTypes is the dialect-neutral types.
+var neutralTypes = map[string]bool{
+	"BOOL":       true,
+	"TINYINT":    true,
+	"SMALLINT":   true,
+	"MEDIUMINT":  true,
+	"INT":        true,
+	"BIGINT":     true,
+	"FLOAT":      true,
+	"DOUBLE":     true,
+	"DECIMAL":    true,
+	"CHAR":       true,
+	"VARCHAR":    true,
+	"TEXT":       true,
+	"MEDIUMTEXT": true,
+	"LONGTEXT":   true,
+	"DATE":       true,
+	"DATETIME":   true,
+	"TIMESTAMP":  true,
+	"TIME":       true,
+	"YEAR":       true,
+	"BINARY":     true,
+	"VARBINARY":  true,
+	"BLOB":       true,
+	"MEDIUMBLOB": true,
+	"LONGBLOB":   true,
+	"JSON":       true,
+	"ENUM":       true,
+	"SET":        true,
+}
+
+// IsNeutralType reports whether typ is the dialect-neutral type.
+// The other types are the raw types of the dialect such as "BINARY(16)".
+// These are rendered as is, so the arguments are the part of the type.
+func IsNeutralType(typ string) bool {
+	return neutralTypes[typ]
+}
+
+// RawType returns the raw type in the canonical form that the letters out of
+// the quoted strings are in upper case, such as "ENUM('a','b')".
+func RawType(typ string) string {
+	buf := []byte(strings.TrimSpace(typ))
+	quoted := false
+	for i, c := range buf {
+		switch {
+		case c == '\'':
+			quoted = !quoted
+		case !quoted && 'a' <= c && c <= 'z':
+			buf[i] = c - 'a' + 'A'
+		}
+	}
+	return string(buf)
+}
+
+// ParseEnumType returns ENUM or SET and its values from the type such as
+// "enum('a','b')". ok is false if typ isn't ENUM or SET.
+func ParseEnumType(typ string) (enumType string, values []string, ok bool) {
+	typ = strings.TrimSpace(typ)
+	open := strings.IndexByte(typ, '(')
+	if open < 0 || !strings.HasSuffix(typ, ")") {
+		return "", nil, false
+	}
+	enumType = strings.ToUpper(strings.TrimSpace(typ[:open]))
+	if enumType != "ENUM" && enumType != "SET" {
+		return "", nil, false
+	}
+	args := strings.TrimSpace(typ[open+1 : len(typ)-1])
+	for len(args) > 0 {
+		if args[0] != '\'' {
+			return "", nil, false
+		}
+		var value []byte
+		i := 1
+		for ; i < len(args); i++ {
+			if args[i] == '\'' {
+				if i+1 < len(args) && args[i+1] == '\'' {
+					i++
+				} else {
+					break
+				}
+			}
+			value = append(value, args[i])
+		}
+		if i >= len(args) {
+			return "", nil, false
+		}
+		values = append(values, string(value))
+		args = strings.TrimSpace(args[i+1:])
+		if strings.HasPrefix(args, ",") {
+			args = strings.TrimSpace(args[1:])
+			if args == "" {
+				return "", nil, false
+			}
+		} else if args != "" {
+			return "", nil, false
+		}
+	}
+	if len(values) == 0 {
+		return "", nil, false
+	}
+	return enumType, values, true
+}
+
+// HasDefault returns whether the column has the default value other than
+// NULL.
+func (c *Column) HasDefault() bool {
+	return c.Default != "" && !(c.DefaultExpr && c.Default == "NULL")
+}
+
+// HasSameDefault returns whether the columns have the same default value.
+// The expressions are compared regardless of the spaces.
+func (c *Column) HasSameDefault(other *Column) bool {
+	if !c.HasDefault() || !other.HasDefault() {
+		return c.HasDefault() == other.HasDefault()
+	}
+	if c.DefaultExpr != other.DefaultExpr {
+		return false
+	}
+	if c.DefaultExpr {
+		return exprKey(c.Default) == exprKey(other.Default)
+	}
+	return c.Default == other.Default
+}
+
+// HasSameGenerated returns whether the columns are generated by the same
+// expression in the same way, or both aren't generated.
+func (c *Column) HasSameGenerated(other *Column) bool {
+	return exprKey(c.Generated) == exprKey(other.Generated) && c.Stored == other.Stored
+}
+
+// currentTimestampRegexp matches CURRENT_TIMESTAMP and its synonyms with the
+// optional fractional seconds precision.
+var currentTimestampRegexp = regexp.MustCompile(`(?i)^(?:(?:CURRENT_TIMESTAMP|LOCALTIME|LOCALTIMESTAMP)(?:\(\s*(\d*)\s*\))?|NOW\(\s*(\d*)\s*\))$`)
+
+// IsCurrentTimestamp returns whether the expression is CURRENT_TIMESTAMP or
+// its synonyms such as NOW() and CURRENT_TIMESTAMP(6).
+func IsCurrentTimestamp(expr string) bool {
+	return currentTimestampRegexp.MatchString(strings.TrimSpace(expr))
+}
+
+// NormalizeDefaultExpr returns the expression of the default value in the
+// canonical form. The enclosing parentheses are removed, the synonyms of
+// CURRENT_TIMESTAMP such as NOW() are CURRENT_TIMESTAMP, and the letters out
+// of the quoted strings are in upper case, such as "UUID()" for "(uuid())".
+func NormalizeDefaultExpr(expr string) string {
+	expr = TrimExpr(expr)
+	if m := currentTimestampRegexp.FindStringSubmatch(expr); m != nil {
+		if fsp := m[1] + m[2]; fsp != "" && fsp != "0" {
+			return "CURRENT_TIMESTAMP(" + fsp + ")"
+		}
+		return "CURRENT_TIMESTAMP"
+	}
+	return RawType(expr)
+}
+
+// TrimExpr returns the expression without the spaces around and the
+// enclosing parentheses.
+func TrimExpr(expr string) string {
+	expr = strings.TrimSpace(expr)
+	for isEnclosedInParens(expr) {
+		expr = strings.TrimSpace(expr[1 : len(expr)-1])
+	}
+	return expr
+}
+
+// jsonOperatorRegexp matches the JSON operators of MySQL such as
+// "DOC->>'$.EMAIL'" in the form of exprKey.
+var jsonOperatorRegexp = regexp.MustCompile(`([A-Z0-9_$.]+)->(>?)('(?:[^']|'')*')`)
+
+// exprKey returns the form of the expression to compare regardless of the
+// letter case, the spaces, the quotes of the identifiers and the enclosing
+// parentheses. The JSON operators are JSON_EXTRACT and JSON_UNQUOTE as well
+// as MySQL reports.
+func exprKey(expr string) string {
+	buf := make([]byte, 0, len(expr))
+	quoted := false
+	for _, c := range []byte(TrimExpr(expr)) {
+		switch {
+		case c == '\'':
+			quoted = !quoted
+		case quoted:
+		case c == ' ', c == '\t', c == '\n', c == '\r', c == '`', c == '"':
+			continue
+		case 'a' <= c && c <= 'z':
+			c -= 'a' - 'A'
+		}
+		buf = append(buf, c)
+	}
+	return jsonOperatorRegexp.ReplaceAllStringFunc(string(buf), func(s string) string {
+		m := jsonOperatorRegexp.FindStringSubmatch(s)
+		if m[2] != "" {
+			return "JSON_UNQUOTE(JSON_EXTRACT(" + m[1] + "," + m[3] + "))"
+		}
+		return "JSON_EXTRACT(" + m[1] + "," + m[3] + ")"
+	})
+}
+
+// isEnclosedInParens returns whether s is enclosed in a pair of parentheses,
+// such as "(a + b)" but not "(a) + (b)".
+func isEnclosedInParens(s string) bool {
+	if !strings.HasPrefix(s, "(") || !strings.HasSuffix(s, ")") {
+		return false
+	}
+	depth := 0
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\'':
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 && i < len(s)-1 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
+
+// IsString returns whether the type of the column is the character type.
+func (c *Column) IsString() bool {
+	switch c.Type {
+	case "CHAR", "VARCHAR", "TEXT", "MEDIUMTEXT", "LONGTEXT":
+		return true
+	}
+	return false
+}
+
+// IsBinary returns whether the type of the column is the binary type.
+func (c *Column) IsBinary() bool {
+	switch c.Type {
+	case "BINARY", "VARBINARY", "BLOB", "MEDIUMBLOB", "LONGBLOB":
+		return true
+	}
+	return false
+}
+
+// IsTime returns whether the type of the column is the date and time type.
+func (c *Column) IsTime() bool {
+	switch c.Type {
+	case "DATE", "DATETIME", "TIMESTAMP", "TIME", "YEAR":
+		return true
+	}
+	return false
+}
+
+// IsEnum returns whether the type of the column is ENUM or SET.
+func (c *Column) IsEnum() bool {
+	return c.Type == "ENUM" || c.Type == "SET"
+}
+
+// HasFractionalSeconds returns whether the type of the column can have the
+// fractional seconds precision.
+func (c *Column) HasFractionalSeconds() bool {
+	switch c.Type {
+	case "DATETIME", "TIMESTAMP", "TIME":
+		return true
+	}
+	return false
+}
+
+// Index is the definition of the index.
+// The primary key is also represented as the index named PrimaryKeyName.
+// The UNIQUE constraint on a single column is represented as
+// Column.Unique instead of the index.
+type Index struct {
+	Name    string
+	Unique  bool
+	Columns []string
+}
+
+// IsPrimaryKey returns whether the index is the primary key.
+func (index *Index) IsPrimaryKey() bool {
+	return strings.ToUpper(index.Name) == PrimaryKeyName
+}
+
+// ForeignKey is the definition of the foreign key constraint.
+// OnDelete and OnUpdate are the referential actions such as "CASCADE" and
+// "SET NULL". The empty string means the default of the database.
+type ForeignKey struct {
+	Name              string
+	Columns           []string
+	ReferencedTable   string
+	ReferencedColumns []string
+	OnDelete          string
+	OnUpdate          string
+}
@@ -0,0 +1,406 @@
+package migu_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/astronoka/migu"
+	"github.com/astronoka/migu/dialect"
+	"github.com/astronoka/migu/schema"
+)
+
+const mysqlSnapshot = "-- MySQL dump 10.13\n" +
+	"/*!40101 SET NAMES utf8 */;\n" +
+	"DROP TABLE IF EXISTS `user`;\n" +
+	"CREATE TABLE `user` (\n" +
+	"  `id` bigint(20) NOT NULL AUTO_INCREMENT,\n" +
+	"  `name` varchar(255) NOT NULL,\n" +
+	"  `age` int(10) unsigned DEFAULT NULL COMMENT 'age',\n" +
+	"  `active` tinyint(1) NOT NULL DEFAULT '1',\n" +
+	"  PRIMARY KEY (`id`),\n" +
+	"  UNIQUE KEY `name` (`name`),\n" +
+	"  KEY `name_age` (`name`,`age`)\n" +
+	") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n"
+
+func writeSnapshot(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "migu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot := filepath.Join(dir, "snapshot.sql")
+	if err := ioutil.WriteFile(snapshot, []byte(mysqlSnapshot), 0644); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return snapshot, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestDiffWithSnapshot(t *testing.T) {
+	snapshot, cleanup := writeSnapshot(t)
+	defer cleanup()
+	for i, v := range []struct {
+		src    string
+		expect []string
+	}{
+		{
+			src: "package migu_test\n" +
+				"type User struct {\n" +
+				"	ID     int64  `migu:\"pk;autoincrement\"`\n" +
+				"	Name   string `migu:\"unique\"`\n" +
+				"	Age    *uint  // age\n" +
+				"	Active bool   `migu:\"default:true\"`\n" +
+				"}\n" +
+				"type UserIndex struct {\n" +
+				"	NameAge interface{} `migu:\"index:name_age,name,age\"`\n" +
+				"}",
+			expect: nil,
+		},
+		{
+			src: "package migu_test\n" +
+				"type User struct {\n" +
+				"	ID     int64  `migu:\"pk;autoincrement\"`\n" +
+				"	Name   string `migu:\"unique\"`\n" +
+				"	Age    *uint  // age\n" +
+				"	Active bool   `migu:\"default:true\"`\n" +
+				"	Email  string\n" +
+				"}",
+			expect: []string{
+				"ALTER TABLE `user` DROP INDEX `name_age`",
+				"ALTER TABLE `user` ADD `email` VARCHAR(255) NOT NULL AFTER `active`",
+			},
+		},
+	} {
+		actual, err := migu.Diff(nil, "", v.src, migu.WithSnapshot(snapshot))
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if !reflect.DeepEqual(actual, v.expect) {
+			t.Errorf("%d: migu.Diff(nil, %#v, WithSnapshot) => %#v; want %#v", i, v.src, actual, v.expect)
+		}
+	}
+}
+
+func TestDiffFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	oldFile := filepath.Join(dir, "old.go")
+	oldSrc := "package migu_test\n" +
+		"type User struct {\n" +
+		"	ID   int64 `migu:\"pk\"`\n" +
+		"	Name string\n" +
+		"}"
+	if err := ioutil.WriteFile(oldFile, []byte(oldSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// the new source is read from the reader as the standard input.
+	newSrc := "package migu_test\n" +
+		"type User struct {\n" +
+		"	ID   int64 `migu:\"pk\"`\n" +
+		"	Name string `migu:\"size:100\"`\n" +
+		"	Age  *int\n" +
+		"}"
+	actual, err := migu.DiffFiles(oldFile, nil, "", strings.NewReader(newSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{
+		"ALTER TABLE `user` ADD `age` INT AFTER `name`",
+		"ALTER TABLE `user` MODIFY `name` VARCHAR(100) NOT NULL",
+	}
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("migu.DiffFiles(%q, nil, \"\", %#v) => %#v; want %#v", oldFile, newSrc, actual, expect)
+	}
+}
+
+func TestDiffWithInvalidTag(t *testing.T) {
+	snapshot, cleanup := writeSnapshot(t)
+	defer cleanup()
+	for i, v := range []struct {
+		field  string
+		expect string
+	}{
+		{"CreatedAt time.Time `migu:\"charset:utf8mb4\"`", "`charset' and `collate' tags aren't available for DATETIME"},
+		{"CreatedAt time.Time `migu:\"precision:3;collate:utf8mb4_bin\"`", "`charset' and `collate' tags aren't available for DATETIME"},
+		{"Balance decimal.Decimal `migu:\"precision:20;scale:8;charset:utf8mb4\"`", "`charset' and `collate' tags aren't available for DECIMAL"},
+		{"Balance string `migu:\"precision:20;scale:8\"`", "`precision' and `scale' tags aren't available for VARCHAR; use the `type' tag"},
+		{"Age int `migu:\"precision:3\"`", "`precision' and `scale' tags aren't available for INT; use the `type' tag"},
+		{"Active *bool `migu:\"scale:1\"`", "`precision' and `scale' tags aren't available for BOOL; use the `type' tag"},
+		{"Age int `migu:\"collate:utf8mb4_bin\"`", "`charset' and `collate' tags aren't available for INT"},
+		{"ID uuid.UUID", "unsupported type uuid.UUID; use the `type' tag"},
+		{"ID sql.Null[uuid.UUID]", "unsupported type uuid.UUID; use the `type' tag"},
+	} {
+		src := "package migu_test\n" +
+			"import \"time\"\n" +
+			"type User struct {\n" +
+			"	" + v.field + "\n" +
+			"}"
+		_, err := migu.Diff(nil, "", src, migu.WithSnapshot(snapshot))
+		if err == nil || !strings.Contains(err.Error(), v.expect) {
+			t.Errorf("%d: migu.Diff(nil, %#v, WithSnapshot) => %v; want %q", i, src, err, v.expect)
+		}
+	}
+}
+
+func TestFprintSQLWithSnapshot(t *testing.T) {
+	snapshot, cleanup := writeSnapshot(t)
+	defer cleanup()
+	var buf bytes.Buffer
+	if err := migu.FprintSQL(&buf, nil, migu.WithSnapshot(snapshot)); err != nil {
+		t.Fatal(err)
+	}
+	expect := "CREATE TABLE `user` (\n" +
+		"  `id` BIGINT NOT NULL PRIMARY KEY AUTO_INCREMENT, `name` VARCHAR(255) NOT NULL UNIQUE, `age` INT UNSIGNED COMMENT 'age', `active` BOOL NOT NULL DEFAULT 1, INDEX `name_age` (`name`,`age`)\n" +
+		");\n\n"
+	if actual := buf.String(); actual != expect {
+		t.Errorf("migu.FprintSQL(nil, WithSnapshot) => %q; want %q", actual, expect)
+	}
+}
+
+func TestFprintFileSQL(t *testing.T) {
+	src := "package migu_test\n" +
+		"type User struct {\n" +
+		"	ID   int64 `migu:\"pk;autoincrement\"`\n" +
+		"	Name string\n" +
+		"}\n" +
+		"type UserIndex struct {\n" +
+		"	Name interface{} `migu:\"index:name_index,name\"`\n" +
+		"}\n" +
+		"type Post struct {\n" +
+		"	ID     int64 `migu:\"pk\"`\n" +
+		"	UserID int64 `migu:\"fk:user.id\"`\n" +
+		"}"
+	for _, v := range []struct {
+		dialect dialect.Dialect
+		expect  string
+	}{
+		{
+			dialect: &dialect.MySQL{},
+			expect: "CREATE TABLE `user` (\n" +
+				"  `id` BIGINT NOT NULL PRIMARY KEY AUTO_INCREMENT, `name` VARCHAR(255) NOT NULL, INDEX `name_index` (`name`)\n" +
+				");\n\n" +
+				"CREATE TABLE `post` (\n" +
+				"  `id` BIGINT NOT NULL PRIMARY KEY, `user_id` BIGINT NOT NULL, CONSTRAINT `fk_post_user_id` FOREIGN KEY (`user_id`) REFERENCES `user` (`id`)\n" +
+				");\n\n",
+		},
+		{
+			dialect: &dialect.SQLite3{},
+			expect: "CREATE TABLE \"user\" (\n" +
+				"  \"id\" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, \"name\" VARCHAR(255) NOT NULL\n" +
+				");\n\n" +
+				"CREATE INDEX \"name_index\" ON \"user\" (\"name\");\n\n" +
+				"CREATE TABLE \"post\" (\n" +
+				"  \"id\" BIGINT NOT NULL PRIMARY KEY, \"user_id\" BIGINT NOT NULL, CONSTRAINT \"fk_post_user_id\" FOREIGN KEY (\"user_id\") REFERENCES \"user\" (\"id\")\n" +
+				");\n\n",
+		},
+	} {
+		var buf bytes.Buffer
+		if err := migu.FprintFileSQL(&buf, "", src, migu.WithDialect(v.dialect)); err != nil {
+			t.Fatal(err)
+		}
+		if actual := buf.String(); actual != v.expect {
+			t.Errorf("migu.FprintFileSQL(%#v, %T) => %q; want %q", src, v.dialect, actual, v.expect)
+		}
+	}
+}
+
+func TestDiffSchemaWithDefaultCharset(t *testing.T) {
+	d := &dialect.MySQL{}
+	column := &schema.Column{Name: "code", Type: "VARCHAR", Size: 20000}
+	expected := &schema.Schema{
+		Tables: []*schema.Table{{Name: "article", Columns: []*schema.Column{column}}},
+	}
+	// the table is created in the default character set of the database.
+	actual := migu.DiffSchema(d, &schema.Schema{Charset: "latin1"}, expected)
+	expect := []string{"CREATE TABLE `article` (\n  `code` VARCHAR(20000) NOT NULL\n)"}
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("migu.DiffSchema(latin1, %#v) => %#v; want %#v", expected, actual, expect)
+	}
+	current := &schema.Schema{
+		Tables: []*schema.Table{{
+			Name:    "article",
+			Columns: []*schema.Column{column},
+			Option:  schema.TableOption{Charset: "latin1"},
+		}},
+		Charset: "latin1",
+	}
+	if actual := migu.DiffSchema(d, current, expected); len(actual) != 0 {
+		t.Errorf("migu.DiffSchema(%#v, %#v) => %#v; want empty", current, expected, actual)
+	}
+}
+
+func TestPlanWithSnapshot(t *testing.T) {
+	snapshot, cleanup := writeSnapshot(t)
+	defer cleanup()
+	src := "package migu_test\n" +
+		"type User struct {\n" +
+		"	ID     int64  `migu:\"pk;autoincrement\"`\n" +
+		"	Name   string `migu:\"unique\"`\n" +
+		"	Age    *int64 // age\n" +
+		"}\n" +
+		"type Post struct {\n" +
+		"	ID int64 `migu:\"pk\"`\n" +
+		"}"
+	ops, err := migu.Plan(nil, "", src, migu.WithSnapshot(snapshot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	type op struct {
+		Type   migu.OpType
+		Table  string
+		Safety migu.Safety
+		SQL    []string
+	}
+	var actual []op
+	for _, o := range ops {
+		actual = append(actual, op{o.Type, o.Table, o.Safety, o.SQL})
+	}
+	expect := []op{
+		{migu.OpCreateTable, "post", migu.Safe, []string{"CREATE TABLE `post` (\n  `id` BIGINT NOT NULL PRIMARY KEY\n)"}},
+		{migu.OpDropIndex, "user", migu.Safe, []string{"ALTER TABLE `user` DROP INDEX `name_age`"}},
+		{migu.OpDropColumn, "user", migu.Destructive, []string{"ALTER TABLE `user` DROP `active`"}},
+		{migu.OpModifyColumn, "user", migu.Safe, []string{"ALTER TABLE `user` MODIFY `age` BIGINT COMMENT 'age'"}},
+	}
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("migu.Plan(nil, %#v, WithSnapshot) => %#v; want %#v", src, actual, expect)
+	}
+	if c := ops[3].CurrentColumn; c == nil || c.Type != "INT" || !c.Unsigned {
+		t.Errorf("ops[3].CurrentColumn => %#v; want INT UNSIGNED", c)
+	}
+}
+
+func TestDiffWithGuard(t *testing.T) {
+	snapshot, cleanup := writeSnapshot(t)
+	defer cleanup()
+	for i, v := range []struct {
+		src     string
+		allowed []string
+		unsafe  []migu.Safety
+	}{
+		{
+			src: "package migu_test\n" +
+				"type User struct {\n" +
+				"	ID     int64  `migu:\"pk;autoincrement\"`\n" +
+				"	Name   string `migu:\"unique\"`\n" +
+				"	Age    *uint64 // age\n" +
+				"	Active bool   `migu:\"default:true\"`\n" +
+				"}",
+			unsafe: nil,
+		},
+		{
+			src: "package migu_test\n" +
+				"type User struct {\n" +
+				"	ID     int64  `migu:\"pk;autoincrement\"`\n" +
+				"	Name   string `migu:\"unique;size:100\"`\n" +
+				"	Age    uint   // age\n" +
+				"	Active bool\n" +
+				"}",
+			unsafe: []migu.Safety{migu.Lossy, migu.Lossy, migu.Lossy},
+		},
+		{
+			src: "package migu_test\n" +
+				"type User struct {\n" +
+				"	ID     int64  `migu:\"pk;autoincrement\"`\n" +
+				"	Name   string `migu:\"unique\"`\n" +
+				"	Age    *int8  // age\n" +
+				"}",
+			unsafe: []migu.Safety{migu.Destructive, migu.Lossy},
+		},
+		{
+			src:     "package migu_test\n",
+			allowed: []string{"user"},
+			unsafe:  nil,
+		},
+	} {
+		_, err := migu.Diff(nil, "", v.src, migu.WithSnapshot(snapshot), migu.WithGuard(v.allowed...))
+		var actual []migu.Safety
+		if err != nil {
+			e, ok := err.(*migu.UnsafeError)
+			if !ok {
+				t.Fatalf("%d: %v", i, err)
+			}
+			for _, op := range e.Ops {
+				actual = append(actual, op.Safety)
+			}
+		}
+		if !reflect.DeepEqual(actual, v.unsafe) {
+			t.Errorf("%d: migu.Diff(nil, %#v, WithGuard(%v)) => %v; want %v", i, v.src, v.allowed, actual, v.unsafe)
+		}
+	}
+}
+
+func TestDiffWithTableFilter(t *testing.T) {
+	snapshot, cleanup := writeSnapshot(t)
+	defer cleanup()
+	src := "package migu_test\n" +
+		"type Post struct {\n" +
+		"	ID int64 `migu:\"pk\"`\n" +
+		"}"
+	for i, v := range []struct {
+		opts   []migu.Option
+		expect []string
+	}{
+		{
+			opts: nil,
+			expect: []string{
+				"CREATE TABLE `post` (\n  `id` BIGINT NOT NULL PRIMARY KEY\n)",
+				"DROP TABLE `user`",
+			},
+		},
+		{
+			opts: []migu.Option{migu.WithExclude("us*")},
+			expect: []string{
+				"CREATE TABLE `post` (\n  `id` BIGINT NOT NULL PRIMARY KEY\n)",
+			},
+		},
+		{
+			opts:   []migu.Option{migu.WithInclude("/^u/")},
+			expect: []string{"DROP TABLE `user`"},
+		},
+		{
+			opts:   []migu.Option{migu.WithInclude("*"), migu.WithExclude("post", "/^user$/")},
+			expect: nil,
+		},
+	} {
+		opts := append(v.opts, migu.WithSnapshot(snapshot))
+		actual, err := migu.Diff(nil, "", src, opts...)
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if !reflect.DeepEqual(actual, v.expect) {
+			t.Errorf("%d: migu.Diff(nil, %#v) => %#v; want %#v", i, src, actual, v.expect)
+		}
+	}
+}
+
+func TestDiffSchemaWithEnumAsText(t *testing.T) {
+	// ENUM and SET are TEXT without the constraint of the values in
+	// PostgreSQL and SQLite3, so changing the values is intentionally
+	// nothing to migrate.
+	current := &schema.Schema{
+		Tables: []*schema.Table{{Name: "user", Columns: []*schema.Column{
+			{Name: "status", Type: "TEXT"},
+			{Name: "perms", Type: "TEXT", Nullable: true},
+		}}},
+	}
+	expected := &schema.Schema{
+		Tables: []*schema.Table{{Name: "user", Columns: []*schema.Column{
+			{Name: "status", Type: "ENUM", Values: []string{"active", "banned"}},
+			{Name: "perms", Type: "SET", Values: []string{"read", "write"}, Nullable: true},
+		}}},
+	}
+	for _, d := range []dialect.Dialect{&dialect.PostgreSQL{}, &dialect.SQLite3{}} {
+		if actual := migu.DiffSchema(d, current, expected); len(actual) != 0 {
+			t.Errorf("migu.DiffSchema(%T, %#v, %#v) => %#v; want empty", d, current, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,591 @@
+package migu_test
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/astronoka/migu"
+	"github.com/astronoka/migu/dialect"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func openSQLite3(t *testing.T) (*sql.DB, func()) {
+	return openSQLite3WithParams(t, "")
+}
+
+func openSQLite3WithParams(t *testing.T, params string) (*sql.DB, func()) {
+	dir, err := ioutil.TempDir("", "migu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlite3DB, err := sql.Open("sqlite3", filepath.Join(dir, "test.db")+params)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return sqlite3DB, func() {
+		sqlite3DB.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestSQLite3Sync(t *testing.T) {
+	sqlite3DB, cleanup := openSQLite3(t)
+	defer cleanup()
+	opt := migu.WithDialect(&dialect.SQLite3{})
+	for i, v := range []struct {
+		src    string
+		expect []string
+	}{
+		{
+			src: "package migu_test\n" +
+				"type User struct {\n" +
+				"	ID   int64 `migu:\"pk;autoincrement\"`\n" +
+				"	Name string\n" +
+				"}\n" +
+				"type UserIndex struct {\n" +
+				"	Name interface{} `migu:\"index:name_index,name\"`\n" +
+				"}",
+			expect: []string{
+				"CREATE TABLE \"user\" (\n" +
+					"  \"id\" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, \"name\" VARCHAR(255) NOT NULL\n" +
+					")",
+				`CREATE INDEX "name_index" ON "user" ("name")`,
+			},
+		},
+		{
+			src: "package migu_test\n" +
+				"type User struct {\n" +
+				"	ID   int64 `migu:\"pk;autoincrement\"`\n" +
+				"	Name string\n" +
+				"	Age  *int\n" +
+				"}\n" +
+				"type UserIndex struct {\n" +
+				"	Name interface{} `migu:\"index:name_index,name\"`\n" +
+				"}",
+			expect: []string{
+				`ALTER TABLE "user" ADD COLUMN "age" INT`,
+			},
+		},
+		{
+			src: "package migu_test\n" +
+				"type User struct {\n" +
+				"	ID   int64 `migu:\"pk;autoincrement\"`\n" +
+				"	Name string `migu:\"size:512\"`\n" +
+				"}\n" +
+				"type UserIndex struct {\n" +
+				"	Name interface{} `migu:\"index:name_index,name\"`\n" +
+				"}",
+			expect: []string{
+				"CREATE TABLE \"new_user\" (\n" +
+					"  \"id\" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, \"name\" VARCHAR(512) NOT NULL\n" +
+					")",
+				`INSERT INTO "new_user" ("id", "name") SELECT "id", "name" FROM "user"`,
+				`DROP TABLE "user"`,
+				`ALTER TABLE "new_user" RENAME TO "user"`,
+				`CREATE INDEX "name_index" ON "user" ("name")`,
+			},
+		},
+		{
+			src:    "package migu_test",
+			expect: []string{`DROP TABLE "user"`},
+		},
+	} {
+		actual, err := migu.Diff(sqlite3DB, "", v.src, opt)
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if !reflect.DeepEqual(actual, v.expect) {
+			t.Fatalf("%d: migu.Diff(db, %q) => %#v; want %#v", i, v.src, actual, v.expect)
+		}
+		if i == 1 {
+			if _, err := sqlite3DB.Exec(`INSERT INTO user (name) VALUES ('alice')`); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := migu.Sync(sqlite3DB, "", v.src, opt); err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		actual, err = migu.Diff(sqlite3DB, "", v.src, opt)
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if len(actual) != 0 {
+			t.Fatalf("%d: migu.Diff(db, %q) after sync => %#v; want empty", i, v.src, actual)
+		}
+		if i == 2 {
+			var name string
+			if err := sqlite3DB.QueryRow(`SELECT name FROM user WHERE id = 1`).Scan(&name); err != nil {
+				t.Fatal(err)
+			}
+			if expect := "alice"; name != expect {
+				t.Fatalf("%d: name => %q after rebuild; want %q", i, name, expect)
+			}
+		}
+	}
+}
+
+func TestSQLite3SyncRename(t *testing.T) {
+	sqlite3DB, cleanup := openSQLite3(t)
+	defer cleanup()
+	opt := migu.WithDialect(&dialect.SQLite3{})
+	for i, v := range []struct {
+		src    string
+		expect []string
+	}{
+		{
+			src: "package migu_test\n" +
+				"type Member struct {\n" +
+				"	ID   int64 `migu:\"pk;autoincrement\"`\n" +
+				"	Name string\n" +
+				"}",
+			expect: []string{
+				"CREATE TABLE \"member\" (\n" +
+					"  \"id\" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, \"name\" VARCHAR(255) NOT NULL\n" +
+					")",
+			},
+		},
+		{
+			src: "package migu_test\n" +
+				"// migu:\"rename:member\"\n" +
+				"type User struct {\n" +
+				"	ID          int64  `migu:\"pk;autoincrement\"`\n" +
+				"	DisplayName string `migu:\"rename:name\"`\n" +
+				"}",
+			expect: []string{
+				`ALTER TABLE "member" RENAME TO "user"`,
+				`ALTER TABLE "user" RENAME COLUMN "name" TO "display_name"`,
+			},
+		},
+	} {
+		actual, err := migu.Diff(sqlite3DB, "", v.src, opt)
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if !reflect.DeepEqual(actual, v.expect) {
+			t.Fatalf("%d: migu.Diff(db, %q) => %#v; want %#v", i, v.src, actual, v.expect)
+		}
+		if err := migu.Sync(sqlite3DB, "", v.src, opt); err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if i == 0 {
+			if _, err := sqlite3DB.Exec(`INSERT INTO member (name) VALUES ('alice')`); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// the rename tags are harmless after the rename.
+		actual, err = migu.Diff(sqlite3DB, "", v.src, opt)
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if len(actual) != 0 {
+			t.Fatalf("%d: migu.Diff(db, %q) after sync => %#v; want empty", i, v.src, actual)
+		}
+	}
+	var name string
+	if err := sqlite3DB.QueryRow(`SELECT display_name FROM user WHERE id = 1`).Scan(&name); err != nil {
+		t.Fatal(err)
+	}
+	if expect := "alice"; name != expect {
+		t.Fatalf("display_name => %q after rename; want %q", name, expect)
+	}
+}
+
+func TestSQLite3SyncRebuildReferencedTable(t *testing.T) {
+	sqlite3DB, cleanup := openSQLite3WithParams(t, "?_foreign_keys=1")
+	defer cleanup()
+	// PRAGMA foreign_keys is the setting of the connection.
+	sqlite3DB.SetMaxOpenConns(1)
+	opt := migu.WithDialect(&dialect.SQLite3{})
+	for i, src := range []string{
+		"package migu_test\n" +
+			"type User struct {\n" +
+			"	ID   int64 `migu:\"pk;autoincrement\"`\n" +
+			"	Name string\n" +
+			"}\n" +
+			"type Post struct {\n" +
+			"	ID     int64 `migu:\"pk;autoincrement\"`\n" +
+			"	UserID int64 `migu:\"fk:user.id;ondelete:cascade\"`\n" +
+			"}",
+		"package migu_test\n" +
+			"type User struct {\n" +
+			"	ID   int64  `migu:\"pk;autoincrement\"`\n" +
+			"	Name string `migu:\"size:512\"`\n" +
+			"}\n" +
+			"type Post struct {\n" +
+			"	ID     int64 `migu:\"pk;autoincrement\"`\n" +
+			"	UserID int64 `migu:\"fk:user.id;ondelete:cascade\"`\n" +
+			"}",
+	} {
+		if err := migu.Sync(sqlite3DB, "", src, opt, migu.WithGuard()); err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if i == 0 {
+			for _, query := range []string{
+				`INSERT INTO user (name) VALUES ('alice')`,
+				`INSERT INTO post (user_id) VALUES (1)`,
+			} {
+				if _, err := sqlite3DB.Exec(query); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+	// dropping the old table mustn't delete the posts by ON DELETE CASCADE.
+	var n int
+	if err := sqlite3DB.QueryRow(`SELECT COUNT(*) FROM post`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if expect := 1; n != expect {
+		t.Fatalf("posts => %d after rebuild; want %d", n, expect)
+	}
+	var enabled bool
+	if err := sqlite3DB.QueryRow(`PRAGMA foreign_keys`).Scan(&enabled); err != nil {
+		t.Fatal(err)
+	}
+	if !enabled {
+		t.Fatalf("PRAGMA foreign_keys => false after sync; want true")
+	}
+	// the violation is rolled back.
+	src := "package migu_test\n" +
+		"type User struct {\n" +
+		"	ID   int64 `migu:\"pk;autoincrement\"`\n" +
+		"}\n" +
+		"type Post struct {\n" +
+		"	ID     int64 `migu:\"pk;autoincrement\"`\n" +
+		"	UserID int64 `migu:\"fk:user.id;ondelete:cascade\"`\n" +
+		"}"
+	if _, err := sqlite3DB.Exec(`PRAGMA foreign_keys = OFF`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sqlite3DB.Exec(`DELETE FROM user`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sqlite3DB.Exec(`PRAGMA foreign_keys = ON`); err != nil {
+		t.Fatal(err)
+	}
+	if err := migu.Sync(sqlite3DB, "", src, opt); err == nil {
+		t.Fatalf("migu.Sync(db, %q) with the orphan post => nil; want error", src)
+	}
+	if sqls, err := migu.Diff(sqlite3DB, "", src, opt); err != nil || len(sqls) == 0 {
+		t.Fatalf("migu.Diff(db, %q) after the failed sync => %#v, %v; want the rebuild", src, sqls, err)
+	}
+}
+
+func TestSQLite3BoolDefault(t *testing.T) {
+	sqlite3DB, cleanup := openSQLite3(t)
+	defer cleanup()
+	if _, err := sqlite3DB.Exec(`CREATE TABLE "user" ("id" INTEGER NOT NULL PRIMARY KEY, "active" BOOLEAN NOT NULL DEFAULT TRUE)`); err != nil {
+		t.Fatal(err)
+	}
+	src := "package migu_test\n" +
+		"type User struct {\n" +
+		"	ID     int  `migu:\"pk\"`\n" +
+		"	Active bool `migu:\"default:true\"`\n" +
+		"}"
+	actual, err := migu.Diff(sqlite3DB, "", src, migu.WithDialect(&dialect.SQLite3{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actual) != 0 {
+		t.Fatalf("migu.Diff(db, %q) => %#v; want empty", src, actual)
+	}
+}
+
+// syncAndDump synchronizes the database with src, and returns the dumped
+// schema as the source.
+func syncAndDump(t *testing.T, sqlite3DB *sql.DB, src string) string {
+	opt := migu.WithDialect(&dialect.SQLite3{})
+	if src != "" {
+		if err := migu.Sync(sqlite3DB, "", src, opt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	if err := migu.Fprint(&buf, sqlite3DB, opt); err != nil {
+		t.Fatal(err)
+	}
+	return "package migu_test\n" + buf.String()
+}
+
+func TestSQLite3FprintEnum(t *testing.T) {
+	sqlite3DB, cleanup := openSQLite3(t)
+	defer cleanup()
+	src := "package migu_test\n" +
+		"type Status string\n" +
+		"const (\n" +
+		"	StatusActive  Status = \"active\"\n" +
+		"	StatusDeleted Status = \"deleted\"\n" +
+		")\n" +
+		"type User struct {\n" +
+		"	ID     int64 `migu:\"pk;autoincrement\"`\n" +
+		"	Status Status\n" +
+		"	Tags   *string `migu:\"set:a,b\"`\n" +
+		"}"
+	dump := syncAndDump(t, sqlite3DB, src)
+	actual, err := migu.Diff(sqlite3DB, "", dump, migu.WithDialect(&dialect.SQLite3{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actual) != 0 {
+		t.Fatalf("migu.Diff(db, %q) => %#v; want empty", dump, actual)
+	}
+	// the values aren't constrained in SQLite3, so changing them is nothing
+	// to migrate, and any value can be inserted.
+	changed := strings.Replace(src, "set:a,b", "set:a", 1)
+	actual, err = migu.Diff(sqlite3DB, "", changed, migu.WithDialect(&dialect.SQLite3{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actual) != 0 {
+		t.Fatalf("migu.Diff(db, %q) => %#v; want empty", changed, actual)
+	}
+	if _, err := sqlite3DB.Exec(`INSERT INTO "user" (status, tags) VALUES ('banned', 'c')`); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSQLite3FprintDeclaredTable(t *testing.T) {
+	sqlite3DB, cleanup := openSQLite3(t)
+	defer cleanup()
+	if _, err := sqlite3DB.Exec(`CREATE TABLE note (id INTEGER PRIMARY KEY, body TEXT NOT NULL, amount REAL)`); err != nil {
+		t.Fatal(err)
+	}
+	dump := syncAndDump(t, sqlite3DB, "")
+	expect := "type Note struct {\n" +
+		"\tID     int\n" +
+		"\tBody   string `migu:\"type:TEXT\"`\n" +
+		"\tAmount *float64\n" +
+		"}\n"
+	if !strings.Contains(dump, expect) {
+		t.Fatalf("migu.Fprint(db) => %q; want %q", dump, expect)
+	}
+	actual, err := migu.Diff(sqlite3DB, "", dump, migu.WithDialect(&dialect.SQLite3{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actual) != 0 {
+		t.Fatalf("migu.Diff(db, %q) => %#v; want empty", dump, actual)
+	}
+}
+
+func TestSQLite3FprintSQL(t *testing.T) {
+	sqlite3DB, cleanup := openSQLite3(t)
+	defer cleanup()
+	opt := migu.WithDialect(&dialect.SQLite3{})
+	src := "package migu_test\n" +
+		"type User struct {\n" +
+		"	ID   int64 `migu:\"pk;autoincrement\"`\n" +
+		"	Name string\n" +
+		"}\n" +
+		"type UserIndex struct {\n" +
+		"	Name interface{} `migu:\"index:name_index,name\"`\n" +
+		"}"
+	if err := migu.Sync(sqlite3DB, "", src, opt); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := migu.FprintSQL(&buf, sqlite3DB, opt); err != nil {
+		t.Fatal(err)
+	}
+	expect := "CREATE TABLE \"user\" (\n" +
+		"  \"id\" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, \"name\" VARCHAR(255) NOT NULL\n" +
+		");\n\n" +
+		"CREATE INDEX \"name_index\" ON \"user\" (\"name\");\n\n"
+	if actual := buf.String(); actual != expect {
+		t.Errorf("migu.FprintSQL(db) => %q; want %q", actual, expect)
+	}
+}
+
+func TestSQLite3FprintDefault(t *testing.T) {
+	sqlite3DB, cleanup := openSQLite3(t)
+	defer cleanup()
+	src := "package migu_test\n" +
+		"import \"time\"\n" +
+		"type Post struct {\n" +
+		"	Token     string    `migu:\"size:36;defaultexpr:(lower(hex(randomblob(16))))\"`\n" +
+		"	Label     string    `migu:\"default:NULL\"`\n" +
+		"	Format    *string   `migu:\"default:now()\"`\n" +
+		"	CreatedAt time.Time `migu:\"default:now()\"`\n" +
+		"}"
+	dump := syncAndDump(t, sqlite3DB, src)
+	// the expression of the string field is always the `defaultexpr' tag.
+	expect := "type Post struct {\n" +
+		"\tToken     string    `migu:\"defaultexpr:LOWER(HEX(RANDOMBLOB(16)));size:36\"`\n" +
+		"\tLabel     string    `migu:\"default:NULL;size:255\"`\n" +
+		"\tFormat    *string   `migu:\"default:now();size:255\"`\n" +
+		"\tCreatedAt time.Time `migu:\"default:CURRENT_TIMESTAMP\"`\n" +
+		"}\n"
+	if !strings.Contains(dump, expect) {
+		t.Fatalf("migu.Fprint(db) => %q; want %q", dump, expect)
+	}
+	actual, err := migu.Diff(sqlite3DB, "", dump, migu.WithDialect(&dialect.SQLite3{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actual) != 0 {
+		t.Fatalf("migu.Diff(db, %q) => %#v; want empty", dump, actual)
+	}
+}
+
+func TestSQLite3FprintNullTypes(t *testing.T) {
+	sqlite3DB, cleanup := openSQLite3(t)
+	defer cleanup()
+	src := "package migu_test\n" +
+		"import \"database/sql\"\n" +
+		"type Event struct {\n" +
+		"	ID        int64 `migu:\"pk;autoincrement\"`\n" +
+		"	Count     sql.NullInt32\n" +
+		"	Level     sql.NullInt16\n" +
+		"	Flags     sql.NullByte\n" +
+		"	StartedAt sql.NullTime\n" +
+		"	Score     sql.Null[float32]\n" +
+		"	Ratio     sql.NullFloat64\n" +
+		"	Data      *[]byte\n" +
+		"}"
+	if err := migu.Sync(sqlite3DB, "", src, migu.WithDialect(&dialect.SQLite3{})); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct {
+		opts   []migu.Option
+		expect string
+	}{
+		{
+			expect: "import \"time\"\n\n" +
+				"type Event struct {\n" +
+				"\tID        int `migu:\"autoincrement\"`\n" +
+				"\tCount     *int\n" +
+				"\tLevel     *int16\n" +
+				"\tFlags     *uint8\n" +
+				"\tStartedAt *time.Time\n" +
+				"\tScore     *float32\n" +
+				"\tRatio     *float64\n" +
+				"\tData      *[]byte\n" +
+				"}\n",
+		},
+		{
+			// the column that doesn't have the Null type is the pointer.
+			opts: []migu.Option{migu.WithNullTypes()},
+			expect: "import \"database/sql\"\n\n" +
+				"type Event struct {\n" +
+				"\tID        int `migu:\"autoincrement\"`\n" +
+				"\tCount     sql.NullInt32\n" +
+				"\tLevel     sql.NullInt16\n" +
+				"\tFlags     sql.NullByte\n" +
+				"\tStartedAt sql.NullTime\n" +
+				"\tScore     sql.Null[float32]\n" +
+				"\tRatio     sql.NullFloat64\n" +
+				"\tData      *[]byte\n" +
+				"}\n",
+		},
+	} {
+		var buf bytes.Buffer
+		opts := append(v.opts, migu.WithDialect(&dialect.SQLite3{}))
+		if err := migu.Fprint(&buf, sqlite3DB, opts...); err != nil {
+			t.Fatal(err)
+		}
+		dump := "package migu_test\n" + buf.String()
+		if !strings.Contains(dump, v.expect) {
+			t.Fatalf("migu.Fprint(db, %d options) => %q; want %q", len(v.opts), dump, v.expect)
+		}
+		actual, err := migu.Diff(sqlite3DB, "", dump, migu.WithDialect(&dialect.SQLite3{}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(actual) != 0 {
+			t.Fatalf("migu.Diff(db, %q) => %#v; want empty", dump, actual)
+		}
+	}
+}
+
+func TestSQLite3FprintDecimal(t *testing.T) {
+	sqlite3DB, cleanup := openSQLite3(t)
+	defer cleanup()
+	src := "package migu_test\n" +
+		"type Account struct {\n" +
+		"	Balance decimal.Decimal     `migu:\"precision:20;scale:8\"`\n" +
+		"	Rate    decimal.NullDecimal `migu:\"scale:4\"`\n" +
+		"}"
+	dump := syncAndDump(t, sqlite3DB, src)
+	// string can't have the `precision' and `scale' tags.
+	expect := "type Account struct {\n" +
+		"\tBalance string  `migu:\"type:DECIMAL(20,8)\"`\n" +
+		"\tRate    *string `migu:\"type:DECIMAL(10,4)\"`\n" +
+		"}\n"
+	if !strings.Contains(dump, expect) {
+		t.Fatalf("migu.Fprint(db) => %q; want %q", dump, expect)
+	}
+	actual, err := migu.Diff(sqlite3DB, "", dump, migu.WithDialect(&dialect.SQLite3{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actual) != 0 {
+		t.Fatalf("migu.Diff(db, %q) => %#v; want empty", dump, actual)
+	}
+}
+
+func TestSQLite3SyncAddNotNullColumn(t *testing.T) {
+	sqlite3DB, cleanup := openSQLite3(t)
+	defer cleanup()
+	opt := migu.WithDialect(&dialect.SQLite3{})
+	src := "package migu_test\n" +
+		"type User struct {\n" +
+		"	ID   int64 `migu:\"pk;autoincrement\"`\n" +
+		"	Name string\n" +
+		"}"
+	if err := migu.Sync(sqlite3DB, "", src, opt); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sqlite3DB.Exec(`INSERT INTO "user" (name) VALUES ('alice'), ('bob')`); err != nil {
+		t.Fatal(err)
+	}
+	// the rows have the zero values of the new NOT NULL columns without the
+	// default.
+	src = "package migu_test\n" +
+		"import \"time\"\n" +
+		"type User struct {\n" +
+		"	ID        int64 `migu:\"pk;autoincrement\"`\n" +
+		"	Name      string\n" +
+		"	Age       int\n" +
+		"	Email     string\n" +
+		"	Role      string `migu:\"enum:member,admin\"`\n" +
+		"	Active    bool   `migu:\"default:true\"`\n" +
+		"	CreatedAt time.Time\n" +
+		"}"
+	if err := migu.Sync(sqlite3DB, "", src, opt); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := sqlite3DB.Query(`SELECT name, age, email, role, active, created_at FROM "user" ORDER BY id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var actual []string
+	for rows.Next() {
+		var (
+			name, email, role string
+			age               int
+			active            bool
+			createdAt         time.Time
+		)
+		if err := rows.Scan(&name, &age, &email, &role, &active, &createdAt); err != nil {
+			t.Fatal(err)
+		}
+		actual = append(actual, fmt.Sprintf("%s,%d,%q,%s,%v,%v", name, age, email, role, active, createdAt.IsZero()))
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{`alice,0,"",member,true,true`, `bob,0,"",member,true,true`}
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("SELECT => %#v; want %#v", actual, expect)
+	}
+}
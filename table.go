@@ -1,24 +1,28 @@
 package migu
 
-// Table is table definitions
+import "github.com/astronoka/migu/schema"
+
+// Table is table definitions.
+//
+// Deprecated: Use schema.Table instead.
 type Table struct {
-	Columns []*columnSchema
+	Columns []*schema.Column
 	Indexes []*Index
 }
 
 func (t Table) HasDatetimeColumn() bool {
 	for _, column := range t.Columns {
-		if column.DataType == "datetime" {
+		if column.Type == "DATETIME" {
 			return true
 		}
 	}
 	return false
 }
 
-func (t Table) ColumnMap() map[string]*columnSchema {
-	m := map[string]*columnSchema{}
+func (t Table) ColumnMap() map[string]*schema.Column {
+	m := map[string]*schema.Column{}
 	for _, column := range t.Columns {
-		m[column.ColumnName] = column
+		m[column.Name] = column
 	}
 	return m
 }
@@ -30,3 +34,15 @@ func (t Table) IndexMap() map[string]*Index {
 	}
 	return m
 }
+
+// schemaTable returns the table of the schema that has the name.
+func (t Table) schemaTable(name string) *schema.Table {
+	table := &schema.Table{
+		Name:    name,
+		Columns: t.Columns,
+	}
+	for _, index := range t.Indexes {
+		table.Indexes = append(table.Indexes, index.schemaIndex())
+	}
+	return table
+}
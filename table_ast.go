@@ -4,36 +4,31 @@ import (
 	"fmt"
 	"go/ast"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/astronoka/migu/dialect"
+	"github.com/astronoka/migu/schema"
 )
 
 type TableAST struct {
 	Name        string
+	Doc         *ast.CommentGroup
 	Schema      *ast.StructType
 	IndexSchema *ast.StructType
-}
 
-func (t *TableAST) HasSchema() bool {
-	return t.Schema != nil
-}
+	// types is the underlying types of the named types and the aliases in
+	// the same file, such as "int64" for `type UserID int64'.
+	types map[string]string
 
-func (t *TableAST) ColumnMap() map[string]*field {
-	m := map[string]*field{}
-	for _, column := range t.MustColumns() {
-		m[column.Name] = column
-	}
-	return m
+	// enums is the values of the named string types that have the constants
+	// in the same file. The fields of these types are ENUM.
+	enums map[string][]string
 }
 
-func (t *TableAST) IndexMap() map[string]*Index {
-	m := map[string]*Index{}
-	for _, index := range t.MustIndexes() {
-		m[index.Name] = index
-	}
-	return m
+func (t *TableAST) HasSchema() bool {
+	return t.Schema != nil
 }
 
 func (t *TableAST) Columns() ([]*field, error) {
@@ -48,13 +43,22 @@ func (t *TableAST) Columns() ([]*field, error) {
 		if err != nil {
 			return nil, fmt.Errorf("migu: TableAST.Columns error: " + err.Error())
 		}
-		f, err := newField(typeName, fld)
+		f, err := newField(t.resolveTypeName(typeName), fld)
 		if err != nil {
 			return nil, fmt.Errorf("migu: TableAST.Columns error: " + err.Error())
 		}
 		if f.Ignore {
 			continue
 		}
+		if values, exist := t.enums[strings.TrimPrefix(typeName, "*")]; exist && f.SQLType == "" && len(f.Values) == 0 {
+			if f.EnumType == "" {
+				f.EnumType = "ENUM"
+			}
+			f.Values = values
+		}
+		if f.EnumType != "" && len(f.Values) == 0 {
+			return nil, fmt.Errorf("migu: TableAST.Columns error: `%s' tag must specify the parameter", strings.ToLower(f.EnumType))
+		}
 		for _, ident := range fld.Names {
 			field := *f
 			field.Name = toSchemaFieldName(ident.Name)
@@ -64,8 +68,29 @@ func (t *TableAST) Columns() ([]*field, error) {
 	return models, nil
 }
 
-func (t *TableAST) Indexes() ([]*Index, error) {
-	indexes := make([]*Index, 0)
+// resolveTypeName returns the underlying type of the named type or the alias
+// in the same file. The pointer to the type is the pointer to the underlying
+// type, such as "*int64" for "*UserID", and so is sql.Null[T].
+func (t *TableAST) resolveTypeName(name string) string {
+	if param := genericNullType(name); param != "" {
+		return "sql.Null[" + t.resolveTypeName(param) + "]"
+	}
+	var pointer string
+	// the circular definitions are stopped by the number of the types.
+	for i := 0; i <= len(t.types); i++ {
+		base := strings.TrimLeft(name, "*")
+		pointer += name[:len(name)-len(base)]
+		underlying, exist := t.types[base]
+		if !exist {
+			break
+		}
+		name = underlying
+	}
+	return pointer + strings.TrimLeft(name, "*")
+}
+
+func (t *TableAST) Indexes() ([]*schema.Index, error) {
+	indexes := make([]*schema.Index, 0)
 	if t.IndexSchema == nil {
 		return indexes, nil
 	}
@@ -86,6 +111,112 @@ func (t *TableAST) Indexes() ([]*Index, error) {
 	return indexes, nil
 }
 
+// Table returns the table of the schema.
+// The `pk' tags of the fields are gathered into the primary key, and the
+// UNIQUE index on a single column that has the same name as the column is
+// treated as the `unique' tag of the field.
+func (t *TableAST) Table() (*schema.Table, error) {
+	fields, err := t.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("migu: TableAST.Table error. " + err.Error())
+	}
+	indexes, err := t.Indexes()
+	if err != nil {
+		return nil, fmt.Errorf("migu: TableAST.Table error. " + err.Error())
+	}
+	table := &schema.Table{
+		Name: toSchemaTableName(t.Name),
+	}
+	if table.RenamedFrom, err = t.renamedFrom(); err != nil {
+		return nil, fmt.Errorf("migu: TableAST.Table error. " + err.Error())
+	}
+	pk := &schema.Index{
+		Name:    schema.PrimaryKeyName,
+		Unique:  true,
+		Columns: []string{},
+	}
+	for _, f := range fields {
+		column, err := f.column()
+		if err != nil {
+			return nil, fmt.Errorf("migu: TableAST.Table error: %s.%s: %v", t.Name, f.Name, err)
+		}
+		table.Columns = append(table.Columns, column)
+		if f.PrimaryKey {
+			pk.Columns = append(pk.Columns, f.Name)
+		}
+		if fk := f.foreignKey(table.Name); fk != nil {
+			table.ForeignKeys = append(table.ForeignKeys, fk)
+		} else if f.OnDelete != "" || f.OnUpdate != "" {
+			return nil, fmt.Errorf("migu: TableAST.Table error: %s.%s has the action of the foreign key without the `%s' tag", t.Name, f.Name, tagForeignKey)
+		}
+	}
+	if len(pk.Columns) > 0 {
+		table.Indexes = append(table.Indexes, pk)
+	}
+	for _, index := range indexes {
+		if index.IsPrimaryKey() && len(pk.Columns) > 0 {
+			return nil, fmt.Errorf("migu: TableAST.Table error: %s has the primary key in both the `%s' tag and the index", t.Name, tagPrimaryKey)
+		}
+		if index.Unique && !index.IsPrimaryKey() && len(index.Columns) == 1 && index.Columns[0] == index.Name {
+			if c := table.Column(index.Name); c != nil {
+				c.Unique = true
+				continue
+			}
+		}
+		table.Indexes = append(table.Indexes, index)
+	}
+	return table, nil
+}
+
+// renamedFrom returns the old name of the table that is specified by the line
+// of the doc comment in the same form as the struct tag as follows.
+//
+//	// migu:"rename:old_name"
+//	type NewName struct { ... }
+func (t *TableAST) renamedFrom() (string, error) {
+	if t.Doc == nil {
+		return "", nil
+	}
+	var name string
+	for _, line := range strings.Split(t.Doc.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "migu:") {
+			continue
+		}
+		for _, opt := range strings.Split(reflect.StructTag(line).Get("migu"), tagSeparater) {
+			optval := strings.SplitN(opt, ":", 2)
+			if optval[0] != tagRename || len(optval) < 2 || optval[1] == "" {
+				return "", fmt.Errorf("%s: unknown table option: `%s'", t.Name, opt)
+			}
+			name = optval[1]
+		}
+	}
+	return name, nil
+}
+
+// ColumnMap returns the columns by the name.
+//
+// Deprecated: Use Table instead.
+func (t *TableAST) ColumnMap() map[string]*field {
+	m := map[string]*field{}
+	for _, column := range t.MustColumns() {
+		m[column.Name] = column
+	}
+	return m
+}
+
+// IndexMap returns the indexes by the name.
+//
+// Deprecated: Use Table instead.
+func (t *TableAST) IndexMap() map[string]*Index {
+	m := map[string]*Index{}
+	for _, index := range t.MustIndexes() {
+		m[index.Name] = index
+	}
+	return m
+}
+
+// Deprecated: Use Columns instead.
 func (t *TableAST) MustColumns() []*field {
 	c, err := t.Columns()
 	if err != nil {
@@ -94,165 +225,162 @@ func (t *TableAST) MustColumns() []*field {
 	return c
 }
 
+// Deprecated: Use Indexes instead.
 func (t *TableAST) MustIndexes() []*Index {
-	i, err := t.Indexes()
+	indexes, err := t.Indexes()
 	if err != nil {
 		panic(err)
 	}
+	i := make([]*Index, len(indexes))
+	for n, index := range indexes {
+		i[n] = newIndex(index)
+	}
 	return i
 }
 
-// see: https://dev.mysql.com/doc/refman/5.6/en/create-table.html
+// CreateTableQuery returns the SQLs to create the table.
+//
+// Deprecated: Use the CreateTable method of dialect.Dialect with Table instead.
 func (t *TableAST) CreateTableQuery(d dialect.Dialect) ([]string, error) {
-	model, err := t.Columns()
+	table, err := t.Table()
 	if err != nil {
 		return nil, fmt.Errorf("migu: TableAST.CreateTableQuery error. " + err.Error())
 	}
-	columns := make([]string, len(model))
-	for i, f := range model {
-		columns[i] = columnSQL(d, f)
-	}
+	return d.CreateTable(table), nil
+}
 
-	indexes, err := t.indexDefinitions(d)
+// AlterTableQueries returns the SQLs to migrate currentTable to the table.
+//
+// Deprecated: Use DiffSchema instead.
+func (t *TableAST) AlterTableQueries(d dialect.Dialect, currentTable *Table) ([]string, error) {
+	diff, err := t.diff(d, currentTable.schemaTable(toSchemaTableName(t.Name)))
 	if err != nil {
-		return nil, fmt.Errorf("migu: TableAST.CreateTableQuery error. " + err.Error())
+		return nil, fmt.Errorf("migu: TableAST.AlterTableQueries error: " + err.Error())
 	}
-
-	createDefinitions := append(columns, indexes...)
-	createTableQuery := fmt.Sprintf(`CREATE TABLE %s (
-  %s
-)`, d.Quote(toSchemaTableName(t.Name)), strings.Join(createDefinitions, ", "))
-
-	return []string{createTableQuery}, nil
+	if diff.IsEmpty() {
+		return nil, nil
+	}
+	return Queries(planAlterTable(d, diff)), nil
 }
 
-func (t *TableAST) indexDefinitions(d dialect.Dialect) ([]string, error) {
-	indexes, err := t.Indexes()
+// GenerateAddFieldSQLs returns the SQLs to add the columns that are not in
+// currentColumMap.
+//
+// Deprecated: Use DiffSchema instead.
+func (t *TableAST) GenerateAddFieldSQLs(d dialect.Dialect, currentColumMap map[string]*schema.Column) ([]string, error) {
+	diff, err := t.diffColumns(d, currentColumMap)
 	if err != nil {
-		return nil, fmt.Errorf("migu: TableAST.indexDefinitions error. " + err.Error())
+		return nil, fmt.Errorf("migu: TableAST.GenerateAddFieldSQLs error: " + err.Error())
 	}
+	return t.alterTable(d, diff, &schema.TableDiff{AddColumns: diff.AddColumns}), nil
+}
 
-	indexDefinitions := make([]string, 0)
-	for _, index := range indexes {
-		indexDefinitions = append(indexDefinitions, index.AsCreateTableDefinition(d))
+// GenerateDropFieldSQLs returns the SQLs to drop the columns in
+// currentColumMap that are not in the table.
+//
+// Deprecated: Use DiffSchema instead.
+func (t *TableAST) GenerateDropFieldSQLs(d dialect.Dialect, currentColumMap map[string]*schema.Column) ([]string, error) {
+	diff, err := t.diffColumns(d, currentColumMap)
+	if err != nil {
+		return nil, fmt.Errorf("migu: TableAST.GenerateDropFieldSQLs error: " + err.Error())
 	}
-	return indexDefinitions, nil
+	return t.alterTable(d, diff, &schema.TableDiff{DropColumns: diff.DropColumns}), nil
 }
 
-func (t *TableAST) AlterTableQueries(d dialect.Dialect, currentTable *Table) ([]string, error) {
-	tableName := toSchemaTableName(t.Name)
-	currentColumMap := currentTable.ColumnMap()
-	currentIndexMap := currentTable.IndexMap()
-	addSQLs, err := t.GenerateAddFieldSQLs(d, currentColumMap)
+// GenerateModifyFieldSQLs returns the SQLs to modify the columns in
+// currentColumMap that differ from the table.
+//
+// Deprecated: Use DiffSchema instead.
+func (t *TableAST) GenerateModifyFieldSQLs(d dialect.Dialect, currentColumMap map[string]*schema.Column) ([]string, error) {
+	diff, err := t.diffColumns(d, currentColumMap)
 	if err != nil {
-		return nil, fmt.Errorf("migu: TableAST.AlterTableQueries error: " + err.Error())
+		return nil, fmt.Errorf("migu: TableAST.GenerateModifyFieldSQLs error: " + err.Error())
 	}
-	dropSQLs, err := t.GenerateDropFieldSQLs(d, currentColumMap)
+	return t.alterTable(d, diff, &schema.TableDiff{ModifyColumns: diff.ModifyColumns}), nil
+}
+
+// GenerateAddIndexSQLs returns the SQLs to add the indexes that are not in
+// currentIndexMap or differ from them.
+//
+// Deprecated: Use DiffSchema instead.
+func (t *TableAST) GenerateAddIndexSQLs(d dialect.Dialect, currentIndexMap map[string]*Index) ([]string, error) {
+	diff, err := t.diffIndexes(d, currentIndexMap)
 	if err != nil {
-		return nil, fmt.Errorf("migu: TableAST.AlterTableQueries error: " + err.Error())
+		return nil, fmt.Errorf("migu: TableAST.GenerateAddIndexSQLs error: " + err.Error())
 	}
-	modifySQLs, err := t.GenerateModifyFieldSQLs(d, currentColumMap)
+	return t.alterTable(d, diff, &schema.TableDiff{AddIndexes: diff.AddIndexes}), nil
+}
+
+// GenerateDropIndexSQLs returns the SQLs to drop the indexes in
+// currentIndexMap that are not in the table or differ from them.
+//
+// Deprecated: Use DiffSchema instead.
+func (t *TableAST) GenerateDropIndexSQLs(d dialect.Dialect, currentIndexMap map[string]*Index) ([]string, error) {
+	diff, err := t.diffIndexes(d, currentIndexMap)
 	if err != nil {
-		return nil, fmt.Errorf("migu: TableAST.AlterTableQueries error: " + err.Error())
+		return nil, fmt.Errorf("migu: TableAST.GenerateDropIndexSQLs error: " + err.Error())
 	}
+	return t.alterTable(d, diff, &schema.TableDiff{DropIndexes: diff.DropIndexes}), nil
+}
 
-	dropIndexSQLs, err := t.GenerateDropIndexSQLs(d, currentIndexMap)
+// diff returns the diff from current to the table.
+func (t *TableAST) diff(d dialect.Dialect, current *schema.Table) (*schema.TableDiff, error) {
+	expected, err := t.Table()
 	if err != nil {
-		return nil, fmt.Errorf("migu: TableAST.AlterTableQueries error: " + err.Error())
+		return nil, err
 	}
-	addIndexSQLs, err := t.GenerateAddIndexSQLs(d, currentIndexMap)
+	return diffTable(d, current, expected), nil
+}
+
+// diffColumns returns the diff of the columns from the current columns to the
+// table. The indexes of the table are regarded as unchanged.
+func (t *TableAST) diffColumns(d dialect.Dialect, columnMap map[string]*schema.Column) (*schema.TableDiff, error) {
+	expected, err := t.Table()
 	if err != nil {
-		return nil, fmt.Errorf("migu: TableAST.AlterTableQueries error: " + err.Error())
+		return nil, err
 	}
-
-	migrations := make([]string, 0)
-	migrations = append(migrations, addSQLs...)
-	migrations = append(migrations, dropSQLs...)
-	migrations = append(migrations, modifySQLs...)
-	migrations = append(migrations, dropIndexSQLs...)
-	migrations = append(migrations, addIndexSQLs...)
-	if len(migrations) <= 0 {
-		return nil, nil
+	current := &schema.Table{
+		Name:    expected.Name,
+		Indexes: expected.Indexes,
 	}
-
-	alterTableQuery := fmt.Sprintf(`ALTER TABLE %s %s`,
-		d.Quote(tableName), strings.Join(migrations, ", "))
-	return []string{alterTableQuery}, nil
-}
-
-func (t *TableAST) GenerateAddFieldSQLs(d dialect.Dialect, currentColumMap map[string]*columnSchema) ([]string, error) {
-	sqls := make([]string, 0)
-	expectedColumns := t.MustColumns()
-	for i, column := range expectedColumns {
-		if _, exist := currentColumMap[column.Name]; exist {
-			continue
-		}
-		position := "FIRST"
-		if i > 0 {
-			prev := expectedColumns[i-1]
-			position = fmt.Sprintf(`AFTER %s`, d.Quote(prev.Name))
-		}
-		sqls = append(sqls, fmt.Sprintf(`ADD %s %s`, columnSQL(d, column), position))
+	names := make([]string, 0, len(columnMap))
+	for name := range columnMap {
+		names = append(names, name)
 	}
-	return sqls, nil
-}
-
-func (t *TableAST) GenerateDropFieldSQLs(d dialect.Dialect, currentColumMap map[string]*columnSchema) ([]string, error) {
-	sqls := make([]string, 0)
-	newColumnMap := t.ColumnMap()
-	for columnName, _ := range currentColumMap {
-		if _, exist := newColumnMap[columnName]; exist {
-			continue
-		}
-		sqls = append(sqls, fmt.Sprintf(`DROP %s`, d.Quote(toSchemaTableName(columnName))))
+	sort.Strings(names)
+	for _, name := range names {
+		current.Columns = append(current.Columns, columnMap[name])
 	}
-	return sqls, nil
+	return diffTable(d, current, expected), nil
 }
 
-func (t *TableAST) GenerateModifyFieldSQLs(d dialect.Dialect, currentColumMap map[string]*columnSchema) ([]string, error) {
-	sqls := make([]string, 0)
-	for _, column := range t.MustColumns() {
-		if _, exist := currentColumMap[column.Name]; !exist {
-			continue
-		}
-		currentColum := currentColumMap[column.Name]
-		if !currentColum.HasDifference(column) {
-			continue
-		}
-		sqls = append(sqls, fmt.Sprintf(`MODIFY %s`, columnSQL(d, column)))
+// diffIndexes returns the diff of the indexes from the current indexes to the
+// table. The columns of the table are regarded as unchanged.
+func (t *TableAST) diffIndexes(d dialect.Dialect, indexMap map[string]*Index) (*schema.TableDiff, error) {
+	expected, err := t.Table()
+	if err != nil {
+		return nil, err
 	}
-	return sqls, nil
-}
-
-func (t *TableAST) GenerateAddIndexSQLs(d dialect.Dialect, currentIndexMap map[string]*Index) ([]string, error) {
-	sqls := make([]string, 0)
-	newIndexMap := t.IndexMap()
-	for indexName, index := range newIndexMap {
-		if currentIndex, exist := currentIndexMap[indexName]; exist {
-			if reflect.DeepEqual(index, currentIndex) {
-				continue
-			}
-		}
-		sqls = append(sqls, fmt.Sprintf("ADD %s", index.AsCreateTableDefinition(d)))
+	current := &schema.Table{
+		Name:    expected.Name,
+		Columns: expected.Columns,
 	}
-	return sqls, nil
+	names := make([]string, 0, len(indexMap))
+	for name := range indexMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		current.Indexes = append(current.Indexes, indexMap[name].schemaIndex())
+	}
+	return diffTable(d, current, expected), nil
 }
 
-func (t *TableAST) GenerateDropIndexSQLs(d dialect.Dialect, currentIndexMap map[string]*Index) ([]string, error) {
-	sqls := make([]string, 0)
-	newIndexMap := t.IndexMap()
-	for indexName, index := range currentIndexMap {
-		if newIndex, exist := newIndexMap[indexName]; exist {
-			if reflect.DeepEqual(index, newIndex) {
-				continue
-			}
-		}
-
-		if index.isPrimaryKey() {
-			sqls = append(sqls, "DROP PRIMARY KEY")
-		} else {
-			sqls = append(sqls, fmt.Sprintf(`DROP INDEX %s`, d.Quote(indexName)))
-		}
+// alterTable returns the SQLs of the part of the diff.
+func (t *TableAST) alterTable(d dialect.Dialect, diff, change *schema.TableDiff) []string {
+	if change.IsEmpty() {
+		return []string{}
 	}
-	return sqls, nil
+	change.Current, change.Expected = diff.Current, diff.Expected
+	return d.AlterTable(change)
 }
@@ -0,0 +1,3 @@
+ALTER TABLE `user` ADD `nickname` VARCHAR(255) COMMENT 'optional' AFTER `id`;
+ALTER TABLE `user` ADD `age` INT NOT NULL DEFAULT 20 AFTER `name`;
+ALTER TABLE `user` ADD `note` VARCHAR(255) AFTER `age`;
@@ -0,0 +1,9 @@
+package schema
+
+type User struct {
+	ID       int64   `migu:"pk;autoincrement"`
+	Nickname *string // optional
+	Name     string
+	Age      int `migu:"default:20"`
+	Note     *string
+}
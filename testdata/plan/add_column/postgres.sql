@@ -0,0 +1,4 @@
+ALTER TABLE "user" ADD COLUMN "nickname" VARCHAR(255);
+COMMENT ON COLUMN "user"."nickname" IS 'optional';
+ALTER TABLE "user" ADD COLUMN "age" INTEGER NOT NULL DEFAULT 20;
+ALTER TABLE "user" ADD COLUMN "note" VARCHAR(255);
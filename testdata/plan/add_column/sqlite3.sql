@@ -0,0 +1,3 @@
+ALTER TABLE "user" ADD COLUMN "nickname" VARCHAR(255);
+ALTER TABLE "user" ADD COLUMN "age" INT NOT NULL DEFAULT 20;
+ALTER TABLE "user" ADD COLUMN "note" VARCHAR(255);
@@ -0,0 +1,18 @@
+package schema
+
+type User struct {
+	ID   int64 `migu:"pk;autoincrement"`
+	Name string
+}
+
+type Post struct {
+	ID       int64 `migu:"pk;autoincrement"`
+	UserID   int64 `migu:"fk:user.id;ondelete:cascade"`
+	Title    string
+	AuthorID int64 `migu:"fk:author.id"`
+}
+
+type Author struct {
+	ID   int64 `migu:"pk;autoincrement"`
+	Name string
+}
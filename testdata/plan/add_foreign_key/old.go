@@ -0,0 +1,12 @@
+package schema
+
+type User struct {
+	ID   int64 `migu:"pk;autoincrement"`
+	Name string
+}
+
+type Post struct {
+	ID     int64 `migu:"pk;autoincrement"`
+	UserID int64
+	Title  string
+}
@@ -0,0 +1,6 @@
+CREATE TABLE "author" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL PRIMARY KEY, "name" VARCHAR(255) NOT NULL
+);
+ALTER TABLE "post" ADD COLUMN "author_id" BIGINT NOT NULL;
+ALTER TABLE "post" ADD CONSTRAINT "fk_post_author_id" FOREIGN KEY ("author_id") REFERENCES "author" ("id");
+ALTER TABLE "post" ADD CONSTRAINT "fk_post_user_id" FOREIGN KEY ("user_id") REFERENCES "user" ("id") ON DELETE CASCADE;
@@ -0,0 +1,9 @@
+CREATE TABLE "author" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "name" VARCHAR(255) NOT NULL
+);
+CREATE TABLE "new_post" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "user_id" BIGINT NOT NULL, "title" VARCHAR(255) NOT NULL, "author_id" BIGINT NOT NULL, CONSTRAINT "fk_post_author_id" FOREIGN KEY ("author_id") REFERENCES "author" ("id"), CONSTRAINT "fk_post_user_id" FOREIGN KEY ("user_id") REFERENCES "user" ("id") ON DELETE CASCADE
+);
+INSERT INTO "new_post" ("id", "user_id", "title", "author_id") SELECT "id", "user_id", "title", 0 FROM "post";
+DROP TABLE "post";
+ALTER TABLE "new_post" RENAME TO "post";
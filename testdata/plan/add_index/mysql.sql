@@ -0,0 +1,2 @@
+ALTER TABLE `user` ADD UNIQUE `email_index` (`email`);
+ALTER TABLE `user` ADD INDEX `name_index` (`name`);
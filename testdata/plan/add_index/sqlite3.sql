@@ -0,0 +1,2 @@
+CREATE UNIQUE INDEX "email_index" ON "user" ("email");
+CREATE INDEX "name_index" ON "user" ("name");
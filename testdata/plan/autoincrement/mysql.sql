@@ -0,0 +1,2 @@
+ALTER TABLE `post` MODIFY `id` BIGINT NOT NULL;
+ALTER TABLE `user` MODIFY `id` BIGINT NOT NULL AUTO_INCREMENT;
@@ -0,0 +1,11 @@
+package schema
+
+type User struct {
+	ID   int64 `migu:"pk"`
+	Name string
+}
+
+type Post struct {
+	ID    int64 `migu:"pk;autoincrement"`
+	Title string
+}
@@ -0,0 +1,4 @@
+ALTER TABLE "post" ALTER COLUMN "id" DROP IDENTITY IF EXISTS, ALTER COLUMN "id" DROP DEFAULT;
+DROP SEQUENCE IF EXISTS "post_id_seq";
+ALTER TABLE "user" ALTER COLUMN "id" ADD GENERATED BY DEFAULT AS IDENTITY;
+SELECT setval(pg_get_serial_sequence('"user"', 'id'), COALESCE(MAX("id"), 0) + 1, false) FROM "user";
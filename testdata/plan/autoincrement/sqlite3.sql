@@ -0,0 +1,12 @@
+CREATE TABLE "new_post" (
+  "id" BIGINT NOT NULL PRIMARY KEY, "title" VARCHAR(255) NOT NULL
+);
+INSERT INTO "new_post" ("id", "title") SELECT "id", "title" FROM "post";
+DROP TABLE "post";
+ALTER TABLE "new_post" RENAME TO "post";
+CREATE TABLE "new_user" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "name" VARCHAR(255) NOT NULL
+);
+INSERT INTO "new_user" ("id", "name") SELECT "id", "name" FROM "user";
+DROP TABLE "user";
+ALTER TABLE "new_user" RENAME TO "user";
@@ -0,0 +1,4 @@
+ALTER TABLE `token` ADD `digest` BINARY(16) NOT NULL AFTER `hash`;
+ALTER TABLE `token` ADD `payload` MEDIUMBLOB AFTER `digest`;
+ALTER TABLE `token` ADD `archive` LONGBLOB NOT NULL AFTER `payload`;
+ALTER TABLE `token` MODIFY `hash` VARBINARY(32) NOT NULL;
@@ -0,0 +1,9 @@
+package schema
+
+type Token struct {
+	ID      int64   `migu:"pk;autoincrement"`
+	Hash    []byte  `migu:"size:32"`
+	Digest  []byte  `migu:"type:binary(16)"`
+	Payload *[]byte `migu:"size:1048576"`
+	Archive []byte  `migu:"size:4294967295"`
+}
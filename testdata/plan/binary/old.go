@@ -0,0 +1,6 @@
+package schema
+
+type Token struct {
+	ID   int64 `migu:"pk;autoincrement"`
+	Hash []byte
+}
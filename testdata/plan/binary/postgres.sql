@@ -0,0 +1,3 @@
+ALTER TABLE "token" ADD COLUMN "digest" BYTEA NOT NULL;
+ALTER TABLE "token" ADD COLUMN "payload" BYTEA;
+ALTER TABLE "token" ADD COLUMN "archive" BYTEA NOT NULL;
@@ -0,0 +1,6 @@
+CREATE TABLE "new_token" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "hash" VARBINARY(32) NOT NULL, "digest" BINARY(16) NOT NULL, "payload" VARBINARY(1048576), "archive" VARBINARY(4294967295) NOT NULL
+);
+INSERT INTO "new_token" ("id", "hash", "digest", "archive") SELECT "id", "hash", X'', X'' FROM "token";
+DROP TABLE "token";
+ALTER TABLE "new_token" RENAME TO "token";
@@ -0,0 +1,2 @@
+ALTER TABLE "post" DROP CONSTRAINT "fk_post_user_id";
+ALTER TABLE "post" ADD CONSTRAINT "fk_post_user_id" FOREIGN KEY ("user_id") REFERENCES "user" ("id");
@@ -0,0 +1,6 @@
+CREATE TABLE "new_post" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "user_id" BIGINT NOT NULL, "title" VARCHAR(255) NOT NULL, CONSTRAINT "fk_post_user_id" FOREIGN KEY ("user_id") REFERENCES "user" ("id")
+);
+INSERT INTO "new_post" ("id", "user_id", "title") SELECT "id", "user_id", "title" FROM "post";
+DROP TABLE "post";
+ALTER TABLE "new_post" RENAME TO "post";
@@ -0,0 +1,2 @@
+ALTER TABLE `user` DROP INDEX `name_index`;
+ALTER TABLE `user` ADD INDEX `name_index` (`name`,`email`);
@@ -0,0 +1,2 @@
+DROP INDEX "name_index";
+CREATE INDEX "name_index" ON "user" ("name","email");
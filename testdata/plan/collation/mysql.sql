@@ -0,0 +1,4 @@
+ALTER TABLE `account` ADD `code` VARCHAR(8) CHARACTER SET ascii COLLATE ascii_bin NOT NULL AFTER `note`;
+ALTER TABLE `account` MODIFY `token` VARCHAR(64) COLLATE utf8mb4_bin NOT NULL;
+ALTER TABLE `account` MODIFY `name` VARCHAR(255) CHARACTER SET utf8mb4 NOT NULL;
+ALTER TABLE `account` MODIFY `note` VARCHAR(255) NOT NULL;
@@ -0,0 +1,9 @@
+package schema
+
+type Account struct {
+	ID    int64  `migu:"pk;autoincrement"`
+	Token string `migu:"size:64;collate:utf8mb4_bin"`
+	Name  string `migu:"charset:utf8mb4"`
+	Note  string
+	Code  string `migu:"size:8;charset:ascii;collate:ascii_bin"`
+}
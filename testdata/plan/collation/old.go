@@ -0,0 +1,8 @@
+package schema
+
+type Account struct {
+	ID    int64  `migu:"pk;autoincrement"`
+	Token string `migu:"size:64"`
+	Name  string `migu:"charset:latin1"`
+	Note  string `migu:"collate:utf8mb4_unicode_ci"`
+}
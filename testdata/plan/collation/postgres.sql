@@ -0,0 +1 @@
+ALTER TABLE "account" ADD COLUMN "code" VARCHAR(8) NOT NULL;
@@ -0,0 +1,6 @@
+CREATE TABLE "new_account" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "token" VARCHAR(64) NOT NULL, "name" VARCHAR(255) NOT NULL, "note" VARCHAR(255) NOT NULL, "code" VARCHAR(8) NOT NULL
+);
+INSERT INTO "new_account" ("id", "token", "name", "note", "code") SELECT "id", "token", "name", "note", '' FROM "account";
+DROP TABLE "account";
+ALTER TABLE "new_account" RENAME TO "account";
@@ -0,0 +1,18 @@
+package schema
+
+type User struct {
+	ID   int64 `migu:"pk;autoincrement"`
+	Name string
+}
+
+type Comment struct {
+	ID     int64 `migu:"pk;autoincrement"`
+	PostID int64 `migu:"fk:post.id;ondelete:cascade"`
+	Body   string
+}
+
+type Post struct {
+	ID     int64  `migu:"pk;autoincrement"`
+	UserID *int64 `migu:"fk:user.id;ondelete:set null;onupdate:cascade"`
+	Title  string
+}
@@ -0,0 +1,9 @@
+CREATE TABLE "user" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "name" VARCHAR(255) NOT NULL
+);
+CREATE TABLE "post" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "user_id" BIGINT, "title" VARCHAR(255) NOT NULL, CONSTRAINT "fk_post_user_id" FOREIGN KEY ("user_id") REFERENCES "user" ("id") ON DELETE SET NULL ON UPDATE CASCADE
+);
+CREATE TABLE "comment" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "post_id" BIGINT NOT NULL, "body" VARCHAR(255) NOT NULL, CONSTRAINT "fk_comment_post_id" FOREIGN KEY ("post_id") REFERENCES "post" ("id") ON DELETE CASCADE
+);
@@ -0,0 +1,6 @@
+CREATE TABLE `membership` (
+  `user_id` BIGINT NOT NULL, `group_id` BIGINT NOT NULL, `role` VARCHAR(255) NOT NULL DEFAULT 'member', PRIMARY KEY (`user_id`,`group_id`)
+);
+CREATE TABLE `user` (
+  `id` BIGINT NOT NULL PRIMARY KEY AUTO_INCREMENT, `name` VARCHAR(255) NOT NULL UNIQUE, `email` VARCHAR(512) NOT NULL, `age` TINYINT UNSIGNED COMMENT 'age in years', `score` DOUBLE NOT NULL, `active` BOOL NOT NULL DEFAULT 1, `bio` MEDIUMTEXT NOT NULL, `created_at` DATETIME NOT NULL, INDEX `age_index` (`age`), UNIQUE `name_email` (`name`,`email`)
+);
@@ -0,0 +1,25 @@
+package schema
+
+import "time"
+
+type User struct {
+	ID        int64  `migu:"pk;autoincrement"`
+	Name      string `migu:"unique"`
+	Email     string `migu:"size:512"`
+	Age       *uint8 // age in years
+	Score     float64
+	Active    bool   `migu:"default:true"`
+	Bio       string `migu:"size:70000"`
+	CreatedAt time.Time
+}
+
+type UserIndex struct {
+	NameEmail interface{} `migu:"index:name_email,name,email;unique"`
+	Age       interface{} `migu:"index:age_index,age"`
+}
+
+type Membership struct {
+	UserID  int64  `migu:"pk"`
+	GroupID int64  `migu:"pk"`
+	Role    string `migu:"default:member"`
+}
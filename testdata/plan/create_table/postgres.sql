@@ -0,0 +1,9 @@
+CREATE TABLE "membership" (
+  "user_id" BIGINT NOT NULL, "group_id" BIGINT NOT NULL, "role" VARCHAR(255) NOT NULL DEFAULT 'member', PRIMARY KEY ("user_id","group_id")
+);
+CREATE TABLE "user" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL PRIMARY KEY, "name" VARCHAR(255) NOT NULL UNIQUE, "email" VARCHAR(512) NOT NULL, "age" SMALLINT, "score" DOUBLE PRECISION NOT NULL, "active" BOOLEAN NOT NULL DEFAULT TRUE, "bio" VARCHAR(70000) NOT NULL, "created_at" TIMESTAMP NOT NULL
+);
+CREATE INDEX "age_index" ON "user" ("age");
+CREATE UNIQUE INDEX "name_email" ON "user" ("name","email");
+COMMENT ON COLUMN "user"."age" IS 'age in years';
@@ -0,0 +1,8 @@
+CREATE TABLE "membership" (
+  "user_id" BIGINT NOT NULL, "group_id" BIGINT NOT NULL, "role" VARCHAR(255) NOT NULL DEFAULT 'member', PRIMARY KEY ("user_id","group_id")
+);
+CREATE TABLE "user" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "name" VARCHAR(255) NOT NULL UNIQUE, "email" VARCHAR(512) NOT NULL, "age" TINYINT UNSIGNED, "score" DOUBLE NOT NULL, "active" BOOLEAN NOT NULL DEFAULT 1, "bio" VARCHAR(70000) NOT NULL, "created_at" DATETIME NOT NULL
+);
+CREATE INDEX "age_index" ON "user" ("age");
+CREATE UNIQUE INDEX "name_email" ON "user" ("name","email");
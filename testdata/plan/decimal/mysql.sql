@@ -0,0 +1,3 @@
+ALTER TABLE `account` ADD `rate` DECIMAL(10,4) AFTER `balance`;
+ALTER TABLE `account` ADD `amount` DECIMAL(10,0) NOT NULL AFTER `rate`;
+ALTER TABLE `account` MODIFY `balance` DECIMAL(20,8) NOT NULL;
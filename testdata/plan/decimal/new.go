@@ -0,0 +1,8 @@
+package schema
+
+type Account struct {
+	ID      int64            `migu:"pk;autoincrement"`
+	Balance decimal.Decimal  `migu:"precision:20;scale:8"`
+	Rate    *decimal.Decimal `migu:"scale:4"`
+	Amount  decimal.Decimal
+}
@@ -0,0 +1,6 @@
+package schema
+
+type Account struct {
+	ID      int64           `migu:"pk;autoincrement"`
+	Balance decimal.Decimal `migu:"precision:10;scale:2"`
+}
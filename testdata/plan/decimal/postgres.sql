@@ -0,0 +1,3 @@
+ALTER TABLE "account" ADD COLUMN "rate" NUMERIC(10,4);
+ALTER TABLE "account" ADD COLUMN "amount" NUMERIC(10,0) NOT NULL;
+ALTER TABLE "account" ALTER COLUMN "balance" TYPE NUMERIC(20,8) USING "balance"::NUMERIC(20,8);
@@ -0,0 +1,6 @@
+CREATE TABLE "new_account" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "balance" DECIMAL(20,8) NOT NULL, "rate" DECIMAL(10,4), "amount" DECIMAL(10,0) NOT NULL
+);
+INSERT INTO "new_account" ("id", "balance", "amount") SELECT "id", "balance", 0 FROM "account";
+DROP TABLE "account";
+ALTER TABLE "new_account" RENAME TO "account";
@@ -0,0 +1,5 @@
+ALTER TABLE `post` ADD `token` VARCHAR(36) NOT NULL DEFAULT (UUID()) AFTER `note`;
+ALTER TABLE `post` ADD `label` VARCHAR(255) NOT NULL DEFAULT 'NULL' AFTER `token`;
+ALTER TABLE `post` ADD `format` VARCHAR(255) NOT NULL DEFAULT 'now()' AFTER `label`;
+ALTER TABLE `post` ADD `publish_at` DATETIME NOT NULL DEFAULT '2000-01-01 00:00:00' AFTER `deleted_at`;
+ALTER TABLE `post` MODIFY `updated_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6);
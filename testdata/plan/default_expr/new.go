@@ -0,0 +1,16 @@
+package schema
+
+import "time"
+
+type Post struct {
+	ID        int64      `migu:"pk;autoincrement"`
+	Title     string     `migu:"default:untitled"`
+	Note      *string    `migu:"defaultexpr:NULL"`
+	Token     string     `migu:"size:36;defaultexpr:(UUID())"`
+	Label     string     `migu:"default:NULL"`
+	Format    string     `migu:"default:now()"`
+	CreatedAt time.Time  `migu:"default:now()"`
+	UpdatedAt time.Time  `migu:"precision:6;default:CURRENT_TIMESTAMP(6);onupdate:CURRENT_TIMESTAMP(6)"`
+	DeletedAt *time.Time `migu:"default:NULL"`
+	PublishAt time.Time  `migu:"default:2000-01-01 00:00:00"`
+}
@@ -0,0 +1,12 @@
+package schema
+
+import "time"
+
+type Post struct {
+	ID        int64  `migu:"pk;autoincrement"`
+	Title     string `migu:"default:untitled"`
+	Note      *string
+	CreatedAt time.Time  `migu:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time  `migu:"default:CURRENT_TIMESTAMP"`
+	DeletedAt *time.Time `migu:"default:NULL"`
+}
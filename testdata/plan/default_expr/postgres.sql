@@ -0,0 +1,5 @@
+ALTER TABLE "post" ADD COLUMN "token" VARCHAR(36) NOT NULL DEFAULT (UUID());
+ALTER TABLE "post" ADD COLUMN "label" VARCHAR(255) NOT NULL DEFAULT 'NULL';
+ALTER TABLE "post" ADD COLUMN "format" VARCHAR(255) NOT NULL DEFAULT 'now()';
+ALTER TABLE "post" ADD COLUMN "publish_at" TIMESTAMP NOT NULL DEFAULT '2000-01-01 00:00:00';
+ALTER TABLE "post" ALTER COLUMN "updated_at" TYPE TIMESTAMP(6) USING "updated_at"::TIMESTAMP(6), ALTER COLUMN "updated_at" SET DEFAULT CURRENT_TIMESTAMP(6);
@@ -0,0 +1,6 @@
+CREATE TABLE "new_post" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "title" VARCHAR(255) NOT NULL DEFAULT 'untitled', "note" VARCHAR(255) DEFAULT NULL, "token" VARCHAR(36) NOT NULL DEFAULT (UUID()), "label" VARCHAR(255) NOT NULL DEFAULT 'NULL', "format" VARCHAR(255) NOT NULL DEFAULT 'now()', "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP, "updated_at" DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP, "deleted_at" DATETIME DEFAULT NULL, "publish_at" DATETIME NOT NULL DEFAULT '2000-01-01 00:00:00'
+);
+INSERT INTO "new_post" ("id", "title", "note", "created_at", "updated_at", "deleted_at") SELECT "id", "title", "note", "created_at", "updated_at", "deleted_at" FROM "post";
+DROP TABLE "post";
+ALTER TABLE "new_post" RENAME TO "post";
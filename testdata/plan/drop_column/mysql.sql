@@ -0,0 +1,2 @@
+ALTER TABLE `user` DROP `age`;
+ALTER TABLE `user` DROP `email`;
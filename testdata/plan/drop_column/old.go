@@ -0,0 +1,8 @@
+package schema
+
+type User struct {
+	ID    int64 `migu:"pk;autoincrement"`
+	Name  string
+	Age   *int
+	Email string
+}
@@ -0,0 +1,6 @@
+CREATE TABLE "new_user" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "name" VARCHAR(255) NOT NULL
+);
+INSERT INTO "new_user" ("id", "name") SELECT "id", "name" FROM "user";
+DROP TABLE "user";
+ALTER TABLE "new_user" RENAME TO "user";
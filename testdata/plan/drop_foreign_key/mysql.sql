@@ -0,0 +1,2 @@
+ALTER TABLE `post` DROP FOREIGN KEY `fk_post_user_id`;
+DROP TABLE `user`;
@@ -0,0 +1,7 @@
+package schema
+
+type Post struct {
+	ID     int64 `migu:"pk;autoincrement"`
+	UserID int64
+	Title  string
+}
@@ -0,0 +1,2 @@
+ALTER TABLE `user` DROP INDEX `email_index`;
+ALTER TABLE `user` DROP INDEX `name_index`;
@@ -0,0 +1,2 @@
+DROP INDEX "email_index";
+DROP INDEX "name_index";
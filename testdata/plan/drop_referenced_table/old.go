@@ -0,0 +1,11 @@
+package schema
+
+type Team struct {
+	ID   int64 `migu:"pk;autoincrement"`
+	Name string
+}
+
+type User struct {
+	ID     int64 `migu:"pk;autoincrement"`
+	TeamID int64 `migu:"fk:team.id"`
+}
@@ -0,0 +1,15 @@
+package schema
+
+type User struct {
+	ID int64 `migu:"pk;autoincrement"`
+}
+
+type Post struct {
+	ID    int64 `migu:"pk;autoincrement"`
+	Title string
+}
+
+type Comment struct {
+	ID   int64 `migu:"pk;autoincrement"`
+	Body string
+}
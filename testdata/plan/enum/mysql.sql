@@ -0,0 +1,5 @@
+ALTER TABLE `user` ADD `plan` ENUM('free','pro') AFTER `perms`;
+ALTER TABLE `user` ADD `locale` ENUM('en','ja') AFTER `plan`;
+ALTER TABLE `user` MODIFY `status` ENUM('pending','active','banned') NOT NULL DEFAULT 'active';
+ALTER TABLE `user` MODIFY `role` ENUM('member','admin') NOT NULL;
+ALTER TABLE `user` MODIFY `perms` SET('read','write','delete') NOT NULL;
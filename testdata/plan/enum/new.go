@@ -0,0 +1,26 @@
+package schema
+
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusActive  Status = "active"
+	StatusBanned  Status = "banned"
+)
+
+type Permission string
+
+const (
+	PermissionRead   Permission = "read"
+	PermissionWrite  Permission = "write"
+	PermissionDelete Permission = "delete"
+)
+
+type User struct {
+	ID     int64       `migu:"pk;autoincrement"`
+	Status Status      `migu:"default:active"`
+	Role   string      `migu:"enum:member,admin"`
+	Perms  Permission  `migu:"set"`
+	Plan   *string     `migu:"type:enum('free','pro')"`
+	Locale *Permission `migu:"enum:en,ja"`
+}
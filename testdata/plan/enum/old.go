@@ -0,0 +1,15 @@
+package schema
+
+type Status string
+
+const (
+	StatusActive Status = "active"
+	StatusBanned Status = "banned"
+)
+
+type User struct {
+	ID     int64  `migu:"pk;autoincrement"`
+	Status Status `migu:"default:active"`
+	Role   string `migu:"enum:admin,member"`
+	Perms  string `migu:"set:read,write"`
+}
@@ -0,0 +1,2 @@
+ALTER TABLE "user" ADD COLUMN "plan" TEXT;
+ALTER TABLE "user" ADD COLUMN "locale" TEXT;
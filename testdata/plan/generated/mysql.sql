@@ -0,0 +1,3 @@
+ALTER TABLE `user` ADD `nickname` VARCHAR(255) GENERATED ALWAYS AS (JSON_UNQUOTE(doc->'$.nickname')) VIRTUAL AFTER `email`;
+ALTER TABLE `user` DROP `full_name`, ADD `full_name` VARCHAR(255) GENERATED ALWAYS AS (CONCAT(first_name,' ',last_name)) STORED NOT NULL AFTER `last_name`, DROP INDEX `full_name_index`, ADD INDEX `full_name_index` (`full_name`);
+ALTER TABLE `user` DROP `email`, ADD `email` VARCHAR(255) GENERATED ALWAYS AS (JSON_UNQUOTE(doc->'$.email')) VIRTUAL NOT NULL AFTER `full_name`;
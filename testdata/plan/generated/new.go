@@ -0,0 +1,17 @@
+package schema
+
+import "encoding/json"
+
+type User struct {
+	ID        int64 `migu:"pk;autoincrement"`
+	Doc       json.RawMessage
+	FirstName string
+	LastName  string
+	FullName  string  `migu:"generated:CONCAT(first_name,' ',last_name);stored"`
+	Email     string  `migu:"generated:JSON_UNQUOTE(doc->'$.email')"`
+	Nickname  *string `migu:"generated:JSON_UNQUOTE(doc->'$.nickname')"`
+}
+
+type UserIndex struct {
+	FullName interface{} `migu:"index:full_name_index,full_name"`
+}
@@ -0,0 +1,16 @@
+package schema
+
+import "encoding/json"
+
+type User struct {
+	ID        int64 `migu:"pk;autoincrement"`
+	Doc       json.RawMessage
+	FirstName string
+	LastName  string
+	FullName  string `migu:"generated:CONCAT(first_name,' ',last_name)"`
+	Email     string
+}
+
+type UserIndex struct {
+	FullName interface{} `migu:"index:full_name_index,full_name"`
+}
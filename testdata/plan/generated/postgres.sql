@@ -0,0 +1,4 @@
+ALTER TABLE "user" ADD COLUMN "nickname" VARCHAR(255) GENERATED ALWAYS AS (JSON_UNQUOTE(doc->'$.nickname')) VIRTUAL;
+ALTER TABLE "user" DROP COLUMN "full_name", ADD COLUMN "full_name" VARCHAR(255) GENERATED ALWAYS AS (CONCAT(first_name,' ',last_name)) STORED NOT NULL;
+CREATE INDEX "full_name_index" ON "user" ("full_name");
+ALTER TABLE "user" DROP COLUMN "email", ADD COLUMN "email" VARCHAR(255) GENERATED ALWAYS AS (JSON_UNQUOTE(doc->'$.email')) VIRTUAL NOT NULL;
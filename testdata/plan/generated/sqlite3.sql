@@ -0,0 +1,7 @@
+CREATE TABLE "new_user" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "doc" JSON NOT NULL, "first_name" VARCHAR(255) NOT NULL, "last_name" VARCHAR(255) NOT NULL, "full_name" VARCHAR(255) GENERATED ALWAYS AS (CONCAT(first_name,' ',last_name)) STORED NOT NULL, "email" VARCHAR(255) GENERATED ALWAYS AS (JSON_UNQUOTE(doc->'$.email')) VIRTUAL NOT NULL, "nickname" VARCHAR(255) GENERATED ALWAYS AS (JSON_UNQUOTE(doc->'$.nickname')) VIRTUAL
+);
+INSERT INTO "new_user" ("id", "doc", "first_name", "last_name") SELECT "id", "doc", "first_name", "last_name" FROM "user";
+DROP TABLE "user";
+ALTER TABLE "new_user" RENAME TO "user";
+CREATE INDEX "full_name_index" ON "user" ("full_name");
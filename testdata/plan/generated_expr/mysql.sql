@@ -0,0 +1 @@
+ALTER TABLE `user` MODIFY `full_name` VARCHAR(255) GENERATED ALWAYS AS (CONCAT(last_name,' ',first_name)) STORED NOT NULL;
@@ -0,0 +1,12 @@
+package schema
+
+type User struct {
+	ID        int64 `migu:"pk;autoincrement"`
+	FirstName string
+	LastName  string
+	FullName  string `migu:"generated:CONCAT(first_name,' ',last_name);stored"`
+}
+
+type UserIndex struct {
+	FullName interface{} `migu:"index:full_name_index,full_name"`
+}
@@ -0,0 +1,2 @@
+ALTER TABLE "user" DROP COLUMN "full_name", ADD COLUMN "full_name" VARCHAR(255) GENERATED ALWAYS AS (CONCAT(last_name,' ',first_name)) STORED NOT NULL;
+CREATE INDEX "full_name_index" ON "user" ("full_name");
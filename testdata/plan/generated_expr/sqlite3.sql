@@ -0,0 +1,7 @@
+CREATE TABLE "new_user" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "first_name" VARCHAR(255) NOT NULL, "last_name" VARCHAR(255) NOT NULL, "full_name" VARCHAR(255) GENERATED ALWAYS AS (CONCAT(last_name,' ',first_name)) STORED NOT NULL
+);
+INSERT INTO "new_user" ("id", "first_name", "last_name") SELECT "id", "first_name", "last_name" FROM "user";
+DROP TABLE "user";
+ALTER TABLE "new_user" RENAME TO "user";
+CREATE INDEX "full_name_index" ON "user" ("full_name");
@@ -0,0 +1,12 @@
+package schema
+
+type User struct {
+	ID    int64 `migu:"pk;autoincrement"`
+	Name  string
+	Email string
+}
+
+type UserIndex struct {
+	Name  interface{} `migu:"index:name_index,name"`
+	Email interface{} `migu:"index:email_index,email;unique"`
+}
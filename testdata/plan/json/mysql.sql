@@ -0,0 +1,3 @@
+ALTER TABLE `event` ADD `metadata` JSON AFTER `payload`;
+ALTER TABLE `event` ADD `tags` JSON NOT NULL AFTER `metadata`;
+ALTER TABLE `event` MODIFY `payload` JSON NOT NULL;
@@ -0,0 +1,10 @@
+package schema
+
+import "encoding/json"
+
+type Event struct {
+	ID       int64 `migu:"pk;autoincrement"`
+	Payload  json.RawMessage
+	Metadata *json.RawMessage
+	Tags     string `migu:"type:json"`
+}
@@ -0,0 +1,6 @@
+package schema
+
+type Event struct {
+	ID      int64  `migu:"pk;autoincrement"`
+	Payload string `migu:"size:65535"`
+}
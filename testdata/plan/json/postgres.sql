@@ -0,0 +1,3 @@
+ALTER TABLE "event" ADD COLUMN "metadata" JSONB;
+ALTER TABLE "event" ADD COLUMN "tags" JSONB NOT NULL;
+ALTER TABLE "event" ALTER COLUMN "payload" TYPE JSONB USING "payload"::JSONB;
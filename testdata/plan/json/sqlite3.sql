@@ -0,0 +1,6 @@
+CREATE TABLE "new_event" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "payload" JSON NOT NULL, "metadata" JSON, "tags" JSON NOT NULL
+);
+INSERT INTO "new_event" ("id", "payload", "tags") SELECT "id", "payload", 'null' FROM "event";
+DROP TABLE "event";
+ALTER TABLE "new_event" RENAME TO "event";
@@ -0,0 +1,5 @@
+ALTER TABLE `user` MODIFY `name` VARCHAR(100) NOT NULL;
+ALTER TABLE `user` MODIFY `age` INT UNSIGNED NOT NULL;
+ALTER TABLE `user` MODIFY `note` VARCHAR(255) NOT NULL;
+ALTER TABLE `user` MODIFY `status` VARCHAR(255) NOT NULL DEFAULT 'inactive';
+ALTER TABLE `user` MODIFY `score` FLOAT NOT NULL COMMENT 'total score';
@@ -0,0 +1,10 @@
+package schema
+
+type User struct {
+	ID     int64  `migu:"pk;autoincrement"`
+	Name   string `migu:"size:100"`
+	Age    uint
+	Note   string
+	Status string  `migu:"default:inactive"`
+	Score  float32 // total score
+}
@@ -0,0 +1,10 @@
+package schema
+
+type User struct {
+	ID     int64 `migu:"pk;autoincrement"`
+	Name   string
+	Age    int
+	Note   *string
+	Status string  `migu:"default:active"`
+	Score  float32 // score
+}
@@ -0,0 +1,5 @@
+ALTER TABLE "user" ALTER COLUMN "name" TYPE VARCHAR(100) USING "name"::VARCHAR(100);
+ALTER TABLE "user" ALTER COLUMN "age" TYPE BIGINT USING "age"::BIGINT;
+ALTER TABLE "user" ALTER COLUMN "note" SET NOT NULL;
+ALTER TABLE "user" ALTER COLUMN "status" SET DEFAULT 'inactive';
+COMMENT ON COLUMN "user"."score" IS 'total score';
@@ -0,0 +1,6 @@
+CREATE TABLE "new_user" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "name" VARCHAR(100) NOT NULL, "age" INT UNSIGNED NOT NULL, "note" VARCHAR(255) NOT NULL, "status" VARCHAR(255) NOT NULL DEFAULT 'inactive', "score" FLOAT NOT NULL
+);
+INSERT INTO "new_user" ("id", "name", "age", "note", "status", "score") SELECT "id", "name", "age", "note", "status", "score" FROM "user";
+DROP TABLE "user";
+ALTER TABLE "new_user" RENAME TO "user";
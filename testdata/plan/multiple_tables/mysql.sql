@@ -0,0 +1,10 @@
+ALTER TABLE `animal` ADD `name` VARCHAR(255) NOT NULL AFTER `zoo_id`;
+ALTER TABLE `animal` ADD INDEX `name_index` (`name`);
+CREATE TABLE `ticket` (
+  `id` BIGINT NOT NULL PRIMARY KEY AUTO_INCREMENT, `visitor_id` BIGINT NOT NULL, INDEX `visitor_id_index` (`visitor_id`)
+);
+CREATE TABLE `visitor` (
+  `id` BIGINT NOT NULL PRIMARY KEY AUTO_INCREMENT, `name` VARCHAR(255) NOT NULL
+);
+ALTER TABLE `zoo` ADD `location` VARCHAR(255) AFTER `name`;
+DROP TABLE `keeper`;
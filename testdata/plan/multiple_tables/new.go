@@ -0,0 +1,32 @@
+package schema
+
+type Zoo struct {
+	ID       int64 `migu:"pk;autoincrement"`
+	Name     string
+	Location *string
+}
+
+type Visitor struct {
+	ID   int64 `migu:"pk;autoincrement"`
+	Name string
+}
+
+type Animal struct {
+	ID    int64 `migu:"pk;autoincrement"`
+	ZooID int64
+	Name  string
+}
+
+type Ticket struct {
+	ID        int64 `migu:"pk;autoincrement"`
+	VisitorID int64
+}
+
+type TicketIndex struct {
+	VisitorID interface{} `migu:"index:visitor_id_index,visitor_id"`
+}
+
+type AnimalIndex struct {
+	Name  interface{} `migu:"index:name_index,name"`
+	ZooID interface{} `migu:"index:zoo_id_index,zoo_id"`
+}
@@ -0,0 +1,19 @@
+package schema
+
+type Zoo struct {
+	ID   int64 `migu:"pk;autoincrement"`
+	Name string
+}
+
+type Animal struct {
+	ID    int64 `migu:"pk;autoincrement"`
+	ZooID int64
+}
+
+type Keeper struct {
+	ID int64 `migu:"pk;autoincrement"`
+}
+
+type AnimalIndex struct {
+	ZooID interface{} `migu:"index:zoo_id_index,zoo_id"`
+}
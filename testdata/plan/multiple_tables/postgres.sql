@@ -0,0 +1,11 @@
+ALTER TABLE "animal" ADD COLUMN "name" VARCHAR(255) NOT NULL;
+CREATE INDEX "name_index" ON "animal" ("name");
+CREATE TABLE "ticket" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL PRIMARY KEY, "visitor_id" BIGINT NOT NULL
+);
+CREATE INDEX "visitor_id_index" ON "ticket" ("visitor_id");
+CREATE TABLE "visitor" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL PRIMARY KEY, "name" VARCHAR(255) NOT NULL
+);
+ALTER TABLE "zoo" ADD COLUMN "location" VARCHAR(255);
+DROP TABLE "keeper";
@@ -0,0 +1,17 @@
+CREATE TABLE "new_animal" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "zoo_id" BIGINT NOT NULL, "name" VARCHAR(255) NOT NULL
+);
+INSERT INTO "new_animal" ("id", "zoo_id", "name") SELECT "id", "zoo_id", '' FROM "animal";
+DROP TABLE "animal";
+ALTER TABLE "new_animal" RENAME TO "animal";
+CREATE INDEX "name_index" ON "animal" ("name");
+CREATE INDEX "zoo_id_index" ON "animal" ("zoo_id");
+CREATE TABLE "ticket" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "visitor_id" BIGINT NOT NULL
+);
+CREATE INDEX "visitor_id_index" ON "ticket" ("visitor_id");
+CREATE TABLE "visitor" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "name" VARCHAR(255) NOT NULL
+);
+ALTER TABLE "zoo" ADD COLUMN "location" VARCHAR(255);
+DROP TABLE "keeper";
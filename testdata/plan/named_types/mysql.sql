@@ -0,0 +1,5 @@
+ALTER TABLE `user` ADD `group_id` INT UNSIGNED AFTER `id`;
+ALTER TABLE `user` ADD `name` VARCHAR(64) NOT NULL AFTER `group_id`;
+ALTER TABLE `user` ADD `nickname` VARCHAR(255) AFTER `name`;
+ALTER TABLE `user` ADD `invited_by` BIGINT AFTER `nickname`;
+ALTER TABLE `user` ADD `created_at` DATETIME NOT NULL AFTER `invited_by`;
@@ -0,0 +1,22 @@
+package schema
+
+import "time"
+
+type UserID int64
+
+type GroupID = uint32
+
+type Name string
+
+type LoginID UserID
+
+type Timestamp = time.Time
+
+type User struct {
+	ID        UserID `migu:"pk;autoincrement"`
+	GroupID   *GroupID
+	Name      Name `migu:"size:64"`
+	Nickname  *Name
+	InvitedBy *LoginID
+	CreatedAt Timestamp
+}
@@ -0,0 +1,5 @@
+ALTER TABLE "user" ADD COLUMN "group_id" BIGINT;
+ALTER TABLE "user" ADD COLUMN "name" VARCHAR(64) NOT NULL;
+ALTER TABLE "user" ADD COLUMN "nickname" VARCHAR(255);
+ALTER TABLE "user" ADD COLUMN "invited_by" BIGINT;
+ALTER TABLE "user" ADD COLUMN "created_at" TIMESTAMP NOT NULL;
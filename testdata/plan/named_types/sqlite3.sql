@@ -0,0 +1,6 @@
+CREATE TABLE "new_user" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "group_id" INT UNSIGNED, "name" VARCHAR(64) NOT NULL, "nickname" VARCHAR(255), "invited_by" BIGINT, "created_at" DATETIME NOT NULL
+);
+INSERT INTO "new_user" ("id", "name", "created_at") SELECT "id", '', '0001-01-01 00:00:00' FROM "user";
+DROP TABLE "user";
+ALTER TABLE "new_user" RENAME TO "user";
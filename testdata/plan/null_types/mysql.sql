@@ -0,0 +1,9 @@
+ALTER TABLE `event` ADD `count` INT AFTER `id`;
+ALTER TABLE `event` ADD `level` SMALLINT AFTER `count`;
+ALTER TABLE `event` ADD `flags` TINYINT UNSIGNED AFTER `level`;
+ALTER TABLE `event` ADD `started_at` DATETIME AFTER `flags`;
+ALTER TABLE `event` ADD `ended_at` TIMESTAMP(3) AFTER `started_at`;
+ALTER TABLE `event` ADD `score` FLOAT AFTER `ended_at`;
+ALTER TABLE `event` ADD `user_id` BIGINT AFTER `score`;
+ALTER TABLE `event` ADD `title` VARCHAR(64) AFTER `user_id`;
+ALTER TABLE `event` ADD `payload` BLOB AFTER `title`;
@@ -0,0 +1,18 @@
+package schema
+
+import "database/sql"
+
+type UserID int64
+
+type Event struct {
+	ID        int64 `migu:"pk;autoincrement"`
+	Count     sql.NullInt32
+	Level     sql.NullInt16
+	Flags     sql.NullByte
+	StartedAt sql.NullTime
+	EndedAt   sql.NullTime `migu:"timestamp;precision:3"`
+	Score     sql.Null[float32]
+	UserID    sql.Null[UserID]
+	Title     sql.Null[string] `migu:"size:64"`
+	Payload   sql.Null[[]byte]
+}
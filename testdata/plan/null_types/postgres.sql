@@ -0,0 +1,9 @@
+ALTER TABLE "event" ADD COLUMN "count" INTEGER;
+ALTER TABLE "event" ADD COLUMN "level" SMALLINT;
+ALTER TABLE "event" ADD COLUMN "flags" SMALLINT;
+ALTER TABLE "event" ADD COLUMN "started_at" TIMESTAMP;
+ALTER TABLE "event" ADD COLUMN "ended_at" TIMESTAMP(3) WITH TIME ZONE;
+ALTER TABLE "event" ADD COLUMN "score" REAL;
+ALTER TABLE "event" ADD COLUMN "user_id" BIGINT;
+ALTER TABLE "event" ADD COLUMN "title" VARCHAR(64);
+ALTER TABLE "event" ADD COLUMN "payload" BYTEA;
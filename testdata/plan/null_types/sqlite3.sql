@@ -0,0 +1,9 @@
+ALTER TABLE "event" ADD COLUMN "count" INT;
+ALTER TABLE "event" ADD COLUMN "level" SMALLINT;
+ALTER TABLE "event" ADD COLUMN "flags" TINYINT UNSIGNED;
+ALTER TABLE "event" ADD COLUMN "started_at" DATETIME;
+ALTER TABLE "event" ADD COLUMN "ended_at" TIMESTAMP(3);
+ALTER TABLE "event" ADD COLUMN "score" FLOAT;
+ALTER TABLE "event" ADD COLUMN "user_id" BIGINT;
+ALTER TABLE "event" ADD COLUMN "title" VARCHAR(64);
+ALTER TABLE "event" ADD COLUMN "payload" BLOB;
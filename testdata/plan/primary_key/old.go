@@ -0,0 +1,6 @@
+package schema
+
+type Membership struct {
+	UserID  int64 `migu:"pk"`
+	GroupID int64
+}
@@ -0,0 +1,2 @@
+ALTER TABLE "membership" DROP CONSTRAINT "membership_pkey";
+ALTER TABLE "membership" ADD PRIMARY KEY ("user_id","group_id");
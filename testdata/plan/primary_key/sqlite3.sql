@@ -0,0 +1,6 @@
+CREATE TABLE "new_membership" (
+  "user_id" BIGINT NOT NULL, "group_id" BIGINT NOT NULL, PRIMARY KEY ("user_id","group_id")
+);
+INSERT INTO "new_membership" ("user_id", "group_id") SELECT "user_id", "group_id" FROM "membership";
+DROP TABLE "membership";
+ALTER TABLE "new_membership" RENAME TO "membership";
@@ -0,0 +1,7 @@
+package schema
+
+// migu:"rename:member"
+type User struct {
+	ID    int64  `migu:"pk;autoincrement"`
+	Email string `migu:"rename:mail"`
+}
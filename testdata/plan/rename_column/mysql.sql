@@ -0,0 +1,4 @@
+ALTER TABLE `user` CHANGE `mail` `email` VARCHAR(255) NOT NULL, RENAME INDEX `mail` TO `email`;
+ALTER TABLE `user` CHANGE `age` `years` INT NOT NULL;
+ALTER TABLE `user` CHANGE `nickname` `display_name` VARCHAR(255) NOT NULL;
+ALTER TABLE `user` MODIFY `years` INT UNSIGNED NOT NULL;
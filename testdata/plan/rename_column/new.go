@@ -0,0 +1,13 @@
+package schema
+
+type User struct {
+	ID          int64 `migu:"pk;autoincrement"`
+	Name        string
+	Email       string `migu:"unique;rename:mail"`
+	Years       uint   `migu:"rename:age"`
+	DisplayName string `migu:"rename:nickname"`
+}
+
+type UserIndex struct {
+	NameAge interface{} `migu:"index:name_age,name,years"`
+}
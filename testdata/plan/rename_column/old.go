@@ -0,0 +1,13 @@
+package schema
+
+type User struct {
+	ID       int64 `migu:"pk;autoincrement"`
+	Name     string
+	Mail     string `migu:"unique"`
+	Age      int
+	Nickname string
+}
+
+type UserIndex struct {
+	NameAge interface{} `migu:"index:name_age,name,age"`
+}
@@ -0,0 +1,5 @@
+ALTER TABLE "user" RENAME COLUMN "mail" TO "email";
+ALTER TABLE "user" RENAME CONSTRAINT "user_mail_key" TO "user_email_key";
+ALTER TABLE "user" RENAME COLUMN "age" TO "years";
+ALTER TABLE "user" RENAME COLUMN "nickname" TO "display_name";
+ALTER TABLE "user" ALTER COLUMN "years" TYPE BIGINT USING "years"::BIGINT;
@@ -0,0 +1,10 @@
+ALTER TABLE "user" RENAME COLUMN "mail" TO "email";
+ALTER TABLE "user" RENAME COLUMN "age" TO "years";
+ALTER TABLE "user" RENAME COLUMN "nickname" TO "display_name";
+CREATE TABLE "new_user" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "name" VARCHAR(255) NOT NULL, "email" VARCHAR(255) NOT NULL UNIQUE, "years" INT UNSIGNED NOT NULL, "display_name" VARCHAR(255) NOT NULL
+);
+INSERT INTO "new_user" ("id", "name", "email", "years", "display_name") SELECT "id", "name", "email", "years", "display_name" FROM "user";
+DROP TABLE "user";
+ALTER TABLE "new_user" RENAME TO "user";
+CREATE INDEX "name_age" ON "user" ("name","years");
@@ -0,0 +1,2 @@
+ALTER TABLE `member` RENAME TO `user`;
+ALTER TABLE `user` ADD `name` VARCHAR(255) AFTER `email`;
@@ -0,0 +1,10 @@
+package schema
+
+// User is renamed from Member.
+//
+// migu:"rename:member"
+type User struct {
+	ID    int64  `migu:"pk;autoincrement"`
+	Email string `migu:"unique"`
+	Name  *string
+}
@@ -0,0 +1,6 @@
+package schema
+
+type Member struct {
+	ID    int64  `migu:"pk;autoincrement"`
+	Email string `migu:"unique"`
+}
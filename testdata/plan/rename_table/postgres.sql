@@ -0,0 +1,4 @@
+ALTER TABLE "member" RENAME TO "user";
+ALTER TABLE "user" RENAME CONSTRAINT "member_pkey" TO "user_pkey";
+ALTER TABLE "user" RENAME CONSTRAINT "member_email_key" TO "user_email_key";
+ALTER TABLE "user" ADD COLUMN "name" VARCHAR(255);
@@ -0,0 +1,2 @@
+ALTER TABLE "member" RENAME TO "user";
+ALTER TABLE "user" ADD COLUMN "name" VARCHAR(255);
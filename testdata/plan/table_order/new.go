@@ -0,0 +1,9 @@
+package schema
+
+type Post struct {
+	ID int64 `migu:"pk;autoincrement"`
+}
+
+type User struct {
+	ID int64 `migu:"pk;autoincrement"`
+}
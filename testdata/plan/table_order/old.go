@@ -0,0 +1,9 @@
+package schema
+
+type User struct {
+	ID int64 `migu:"pk;autoincrement"`
+}
+
+type Post struct {
+	ID int64 `migu:"pk;autoincrement"`
+}
@@ -0,0 +1,5 @@
+ALTER TABLE `event` ADD `updated_at` TIMESTAMP(3) NOT NULL AFTER `day`;
+ALTER TABLE `event` ADD `end_day` DATE AFTER `updated_at`;
+ALTER TABLE `event` ADD `start_at` TIME NOT NULL AFTER `end_day`;
+ALTER TABLE `event` ADD `year` YEAR NOT NULL AFTER `start_at`;
+ALTER TABLE `event` MODIFY `created_at` DATETIME(6) NOT NULL;
@@ -0,0 +1,13 @@
+package schema
+
+import "time"
+
+type Event struct {
+	ID        int64      `migu:"pk;autoincrement"`
+	CreatedAt time.Time  `migu:"precision:6"`
+	Day       time.Time  `migu:"date"`
+	UpdatedAt time.Time  `migu:"timestamp;precision:3"`
+	EndDay    *time.Time `migu:"date"`
+	StartAt   string     `migu:"time"`
+	Year      int16      `migu:"year"`
+}
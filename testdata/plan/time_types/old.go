@@ -0,0 +1,9 @@
+package schema
+
+import "time"
+
+type Event struct {
+	ID        int64 `migu:"pk;autoincrement"`
+	CreatedAt time.Time
+	Day       time.Time `migu:"date"`
+}
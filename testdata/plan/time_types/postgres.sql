@@ -0,0 +1,5 @@
+ALTER TABLE "event" ADD COLUMN "updated_at" TIMESTAMP(3) WITH TIME ZONE NOT NULL;
+ALTER TABLE "event" ADD COLUMN "end_day" DATE;
+ALTER TABLE "event" ADD COLUMN "start_at" TIME NOT NULL;
+ALTER TABLE "event" ADD COLUMN "year" SMALLINT NOT NULL;
+ALTER TABLE "event" ALTER COLUMN "created_at" TYPE TIMESTAMP(6) USING "created_at"::TIMESTAMP(6);
@@ -0,0 +1,6 @@
+CREATE TABLE "new_event" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "created_at" DATETIME(6) NOT NULL, "day" DATE NOT NULL, "updated_at" TIMESTAMP(3) NOT NULL, "end_day" DATE, "start_at" TIME NOT NULL, "year" YEAR NOT NULL
+);
+INSERT INTO "new_event" ("id", "created_at", "day", "updated_at", "start_at", "year") SELECT "id", "created_at", "day", '0001-01-01 00:00:00', '00:00:00', 0 FROM "event";
+DROP TABLE "event";
+ALTER TABLE "new_event" RENAME TO "event";
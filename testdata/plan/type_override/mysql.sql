@@ -0,0 +1,5 @@
+ALTER TABLE `item` ADD `price` DECIMAL(12,2) NOT NULL AFTER `code`;
+ALTER TABLE `item` ADD `hash` BINARY(16) NOT NULL AFTER `price`;
+ALTER TABLE `item` ADD `place` POINT AFTER `hash`;
+ALTER TABLE `item` MODIFY `count` MEDIUMINT UNSIGNED NOT NULL;
+ALTER TABLE `item` MODIFY `code` CHAR(3) NOT NULL;
@@ -0,0 +1,10 @@
+package schema
+
+type Item struct {
+	ID    int64   `migu:"pk;autoincrement"`
+	Count uint32  `migu:"type:MEDIUMINT UNSIGNED"`
+	Code  string  `migu:"type:char(3)"`
+	Price string  `migu:"type:decimal(12, 2)"`
+	Hash  string  `migu:"type:binary(16)"`
+	Place *string `migu:"type:point"`
+}
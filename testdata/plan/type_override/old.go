@@ -0,0 +1,7 @@
+package schema
+
+type Item struct {
+	ID    int64 `migu:"pk;autoincrement"`
+	Count int
+	Code  string
+}
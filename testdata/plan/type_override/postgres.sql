@@ -0,0 +1,5 @@
+ALTER TABLE "item" ADD COLUMN "price" NUMERIC(12,2) NOT NULL;
+ALTER TABLE "item" ADD COLUMN "hash" BYTEA NOT NULL;
+ALTER TABLE "item" ADD COLUMN "place" POINT;
+ALTER TABLE "item" ALTER COLUMN "count" TYPE BIGINT USING "count"::BIGINT;
+ALTER TABLE "item" ALTER COLUMN "code" TYPE CHAR(3) USING "code"::CHAR(3);
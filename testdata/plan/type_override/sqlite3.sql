@@ -0,0 +1,6 @@
+CREATE TABLE "new_item" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "count" MEDIUMINT UNSIGNED NOT NULL, "code" CHAR(3) NOT NULL, "price" DECIMAL(12,2) NOT NULL, "hash" BINARY(16) NOT NULL, "place" POINT
+);
+INSERT INTO "new_item" ("id", "count", "code", "price", "hash") SELECT "id", "count", "code", 0, X'' FROM "item";
+DROP TABLE "item";
+ALTER TABLE "new_item" RENAME TO "item";
@@ -0,0 +1,2 @@
+ALTER TABLE `user` MODIFY `name` VARCHAR(255) NOT NULL UNIQUE;
+ALTER TABLE `user` MODIFY `email` VARCHAR(255) NOT NULL, DROP INDEX `email`;
@@ -0,0 +1,7 @@
+package schema
+
+type User struct {
+	ID    int64  `migu:"pk;autoincrement"`
+	Name  string `migu:"unique"`
+	Email string
+}
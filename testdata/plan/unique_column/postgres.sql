@@ -0,0 +1,2 @@
+ALTER TABLE "user" ADD CONSTRAINT "user_name_key" UNIQUE ("name");
+ALTER TABLE "user" DROP CONSTRAINT "user_email_key";
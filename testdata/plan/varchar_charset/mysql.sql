@@ -0,0 +1,13 @@
+ALTER TABLE `article` ADD `summary` MEDIUMTEXT NOT NULL AFTER `id`;
+ALTER TABLE `article` ADD `legacy` TEXT CHARACTER SET utf8 NOT NULL AFTER `summary`;
+ALTER TABLE `article` ADD `note` TEXT CHARACTER SET latin1 NOT NULL AFTER `legacy`;
+ALTER TABLE `article` ADD `body` MEDIUMTEXT NOT NULL AFTER `note`;
+CREATE TABLE `article_code` (
+  `id` BIGINT NOT NULL PRIMARY KEY AUTO_INCREMENT, `code` VARCHAR(60000) COLLATE latin1_bin NOT NULL
+);
+CREATE TABLE `article_legacy` (
+  `legacy` VARCHAR(21844) CHARACTER SET utf8 NOT NULL
+);
+CREATE TABLE `article_title` (
+  `title` VARCHAR(16383) NOT NULL
+);
@@ -0,0 +1,24 @@
+package schema
+
+// the tables are split to keep the size of the row within 64KB.
+
+type Article struct {
+	ID      int64  `migu:"pk;autoincrement"`
+	Summary string `migu:"size:16384"`
+	Legacy  string `migu:"size:21845;charset:utf8"`
+	Note    string `migu:"size:65535;charset:latin1"`
+	Body    string `migu:"size:70000"`
+}
+
+type ArticleTitle struct {
+	Title string `migu:"size:16383"`
+}
+
+type ArticleLegacy struct {
+	Legacy string `migu:"size:21844;charset:utf8"`
+}
+
+type ArticleCode struct {
+	ID   int64  `migu:"pk;autoincrement"`
+	Code string `migu:"size:60000;collate:latin1_bin"`
+}
@@ -0,0 +1,5 @@
+package schema
+
+type Article struct {
+	ID int64 `migu:"pk;autoincrement"`
+}
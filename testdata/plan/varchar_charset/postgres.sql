@@ -0,0 +1,13 @@
+ALTER TABLE "article" ADD COLUMN "summary" VARCHAR(16384) NOT NULL;
+ALTER TABLE "article" ADD COLUMN "legacy" VARCHAR(21845) NOT NULL;
+ALTER TABLE "article" ADD COLUMN "note" VARCHAR(65535) NOT NULL;
+ALTER TABLE "article" ADD COLUMN "body" VARCHAR(70000) NOT NULL;
+CREATE TABLE "article_code" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL PRIMARY KEY, "code" VARCHAR(60000) NOT NULL
+);
+CREATE TABLE "article_legacy" (
+  "legacy" VARCHAR(21844) NOT NULL
+);
+CREATE TABLE "article_title" (
+  "title" VARCHAR(16383) NOT NULL
+);
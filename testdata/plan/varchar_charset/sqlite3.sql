@@ -0,0 +1,15 @@
+CREATE TABLE "new_article" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "summary" VARCHAR(16384) NOT NULL, "legacy" VARCHAR(21845) NOT NULL, "note" VARCHAR(65535) NOT NULL, "body" VARCHAR(70000) NOT NULL
+);
+INSERT INTO "new_article" ("id", "summary", "legacy", "note", "body") SELECT "id", '', '', '', '' FROM "article";
+DROP TABLE "article";
+ALTER TABLE "new_article" RENAME TO "article";
+CREATE TABLE "article_code" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, "code" VARCHAR(60000) NOT NULL
+);
+CREATE TABLE "article_legacy" (
+  "legacy" VARCHAR(21844) NOT NULL
+);
+CREATE TABLE "article_title" (
+  "title" VARCHAR(16383) NOT NULL
+);
@@ -0,0 +1,2 @@
+ALTER TABLE `article` ADD `body` VARCHAR(40000) NOT NULL AFTER `summary`;
+ALTER TABLE `article` ADD `note` MEDIUMTEXT CHARACTER SET utf8mb4 NOT NULL AFTER `body`;
@@ -0,0 +1,9 @@
+package schema
+
+type Article struct {
+	ID      int64  `migu:"pk;autoincrement"`
+	Code    string `migu:"size:20000"`
+	Summary string `migu:"size:65535"`
+	Body    string `migu:"size:40000"`
+	Note    string `migu:"size:20000;charset:utf8mb4"`
+}
@@ -0,0 +1,9 @@
+-- MySQL dump 10.13
+/*!40101 SET NAMES utf8mb4 */;
+DROP TABLE IF EXISTS `article`;
+CREATE TABLE `article` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `code` varchar(20000) NOT NULL,
+  `summary` text NOT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;